@@ -5,15 +5,23 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
-	"github.com/spf13/cobra"
+	"github.com/gitwohq/gitwo/internal/config"
 	"github.com/gitwohq/gitwo/internal/gitutil"
+	"github.com/gitwohq/gitwo/internal/hooks"
+	"github.com/gitwohq/gitwo/internal/pathtmpl"
+	"github.com/gitwohq/gitwo/internal/wt"
+	"github.com/spf13/cobra"
 )
 
 var (
 	addBranchFlag string // deprecated alias for positional branch
 	addWorktrees  string
 	addPath       string
+	addTrack      bool
+	addRemote     string
+	addDetach     bool
 )
 
 // addCmd represents the add command
@@ -24,9 +32,21 @@ var addCmd = &cobra.Command{
 Attach an existing branch to a new worktree. This command will NOT create a branch.
 If you need to create one, use: gitwo new <name> [--start-point <ref>]
 
+A branch that only exists on a remote (a colleague's branch) is attached by
+creating a local branch that tracks it: name it with its remote
+(origin/their-branch) or pass --track. When several remotes have the branch,
+gitwo asks which one to use, or takes --remote.
+
+With --detach any tag, commit or ref is checked out with a detached HEAD in a
+worktree named after it, e.g. to inspect a release. 'gitwo list' shows the
+ref in parentheses in the branch column.
+
 Examples:
   gitwo add feature/foo
   gitwo add release/1.2 --path ./_wt/release-1-2
+  gitwo add origin/their-branch
+  gitwo add their-branch --track --remote upstream
+  gitwo add --detach v1.2.0
 `),
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
@@ -46,37 +66,85 @@ Examples:
 			return fmt.Errorf("this repository has no commits yet.\nMake an initial commit before using 'gitwo add'")
 		}
 
-		// Ensure branch exists (strict with Git semantics)
-		if !gitutil.BranchExists(branch) {
-			base := filepath.Base(branch)
-			return fmt.Errorf("branch %q does not exist.\nTo create it from HEAD: gitwo new %s\nOr from another ref: gitwo new %s --start-point origin/main",
-				branch, base, base)
+		repoPath, err := wt.MainRoot()
+		if err != nil {
+			return err
 		}
+		cfg := loadRepoConfig(cmd.ErrOrStderr(), repoPath)
 
-		// Determine worktree path
-		worktreesDir := addWorktrees
-		if worktreesDir == "" {
-			worktreesDir = ".gitwo"
+		if addDetach {
+			if addTrack || addRemote != "" {
+				return fmt.Errorf("--detach cannot be combined with --track or --remote")
+			}
+			return addDetached(cmd, cfg, repoPath, branch)
+		}
+
+		// A branch that only exists on a remote gets a local branch tracking it
+		var track *wt.RemoteBranch
+		if !wt.LocalBranchExists(branch) {
+			track, err = remoteBranchToTrack(cmd, branch)
+			if err != nil {
+				return err
+			}
+			if track != nil {
+				branch = track.Branch
+				if wt.LocalBranchExists(branch) {
+					if up := wt.BranchUpstream(branch); up != track.Ref() {
+						return fmt.Errorf("local branch %q already exists and does not track %s.\nAttach it with: gitwo add %s", branch, track.Ref(), branch)
+					}
+					track = nil
+				}
+			}
 		}
-		if err := os.MkdirAll(worktreesDir, 0o755); err != nil {
-			return fmt.Errorf("failed to create worktrees dir %q: %w", worktreesDir, err)
+		prefix, name := pathtmpl.SplitBranch(branch, cfg.DefaultBranchPrefix)
+
+		// Ensure branch exists (strict with Git semantics)
+		if track == nil && !gitutil.BranchExists(branch) {
+			hint := name
+			if prefix != cfg.DefaultBranchPrefix {
+				hint = fmt.Sprintf("%s --prefix '%s'", name, prefix)
+			}
+			return fmt.Errorf("branch %q does not exist.\nTo create it: gitwo new %s\nOr from another ref: gitwo new %s --start-point origin/main",
+				branch, hint, hint)
 		}
 
+		// Determine worktree path: --path wins, then --worktrees-dir, then config
 		path := addPath
 		if path == "" {
-			path = gitutil.InferDefaultWorktreePath(worktreesDir, branch)
+			path, err = worktreeTarget(cfg, repoPath, addWorktrees, pathtmpl.Vars{Branch: branch, Name: name, Prefix: prefix})
+			if err != nil {
+				return err
+			}
+		}
+		absPath, err := prepareAddPath(path)
+		if err != nil {
+			return err
 		}
 
-		// Guard: path must not be an existing non-empty directory
-		if fi, err := os.Stat(path); err == nil && fi.IsDir() {
-			entries, _ := os.ReadDir(path)
-			if len(entries) > 0 {
-				return fmt.Errorf("target path %q already exists and is not empty; choose another path or remove it", path)
+		run := hookRun{
+			Event:        hooks.EventPreAdd,
+			Action:       "add",
+			RepoPath:     repoPath,
+			Branch:       branch,
+			WorktreePath: absPath,
+			Dir:          repoPath,
+		}
+		if err := runHooks(cmd.OutOrStdout(), cfg, run); err != nil {
+			return err
+		}
+
+		if track != nil {
+			if err := wt.CreateTrackingBranch(branch, *track); err != nil {
+				return err
 			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Created branch %q tracking %s\n", branch, track.Ref())
 		}
 
 		// Execute: git worktree add <path> <branch>
 		if err := gitutil.GitWorktreeAdd(path, branch); err != nil {
+			if track != nil {
+				_ = wt.DeleteBranch(branch)
+			}
 			// Friendlier message for common cases
 			if strings.Contains(strings.ToLower(err.Error()), "is already checked out at") {
 				return fmt.Errorf("branch %q is already attached to a worktree.\nUse 'git worktree list' to locate it.", branch)
@@ -84,7 +152,16 @@ Examples:
 			return err
 		}
 
-		fmt.Fprintf(cmd.OutOrStdout(), "Attached branch %q at %s\n", branch, path)
+		// Best effort: metadata only adds information
+		_ = wt.WriteMeta(absPath, wt.Meta{Created: time.Now()})
+
+		fmt.Fprintf(cmd.OutOrStdout(), "Attached branch %q at %s\n", branch, displayPath(path))
+
+		run.Event = hooks.EventPostAdd
+		run.Dir = absPath
+		if err := runHooks(cmd.OutOrStdout(), cfg, run); err != nil {
+			return fmt.Errorf("worktree created at %s, but %w", displayPath(path), err)
+		}
 		return nil
 	},
 }
@@ -97,6 +174,122 @@ func init() {
 	_ = addCmd.Flags().MarkDeprecated("branch", "use positional: gitwo add <branch>")
 
 	// Users may still pass --path
-	addCmd.Flags().StringVar(&addPath, "path", "", "explicit worktree path (default: <worktrees-dir>/<basename(branch)>)")
-	addCmd.Flags().StringVar(&addWorktrees, "worktrees-dir", "", "directory to place worktrees (default ./.gitwo)")
+	addCmd.Flags().StringVar(&addPath, "path", "", "explicit worktree path (default: <worktrees-dir>/<name_template>)")
+	addCmd.Flags().StringVar(&addWorktrees, "worktrees-dir", "", "directory to place worktrees (default: worktrees_dir from config)")
+	addCmd.Flags().BoolVar(&noHooks, "no-hooks", false, "skip pre_add/post_add hooks")
+	addCmd.Flags().BoolVar(&addTrack, "track", false, "create a local branch tracking the remote branch of that name")
+	addCmd.Flags().StringVar(&addRemote, "remote", "", "remote to take the branch from when several have it (implies --track)")
+	addCmd.Flags().BoolVar(&addDetach, "detach", false, "check out a tag, commit or ref with a detached HEAD")
+}
+
+// prepareAddPath creates the parent of a new worktree's path and makes sure
+// the path is not a non-empty directory. It returns the absolute path.
+func prepareAddPath(path string) (string, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", fmt.Errorf("failed to create worktrees dir %q: %w", filepath.Dir(path), err)
+	}
+	if fi, err := os.Stat(path); err == nil && fi.IsDir() {
+		entries, _ := os.ReadDir(path)
+		if len(entries) > 0 {
+			return "", fmt.Errorf("target path %q already exists and is not empty; choose another path or remove it", path)
+		}
+	}
+	return filepath.Abs(path)
+}
+
+// addDetached attaches a worktree with a detached HEAD at ref, named after
+// the ref (or the short commit when ref is a commit id)
+func addDetached(cmd *cobra.Command, cfg *config.Config, repoPath, ref string) error {
+	out := cmd.OutOrStdout()
+	head, err := gitutil.ResolveCommit(ref)
+	if err != nil {
+		return err
+	}
+	name := ref
+	if strings.HasPrefix(head, ref) {
+		name = wt.ShortSHA(head)
+	}
+
+	path := addPath
+	if path == "" {
+		path, err = worktreeTarget(cfg, repoPath, addWorktrees, pathtmpl.Vars{Branch: name, Name: name})
+		if err != nil {
+			return err
+		}
+	}
+	absPath, err := prepareAddPath(path)
+	if err != nil {
+		return err
+	}
+
+	run := hookRun{
+		Event:        hooks.EventPreAdd,
+		Action:       "add",
+		RepoPath:     repoPath,
+		WorktreePath: absPath,
+		Dir:          repoPath,
+	}
+	if err := runHooks(out, cfg, run); err != nil {
+		return err
+	}
+
+	if err := gitutil.GitWorktreeAdd("--detach", path, head); err != nil {
+		return err
+	}
+	_ = wt.WriteMeta(absPath, wt.Meta{Created: time.Now(), Detached: &wt.DetachedMeta{Ref: name, Head: head}})
+
+	fmt.Fprintf(out, "Attached %s (detached at %s) at %s\n", ref, wt.ShortSHA(head), displayPath(path))
+
+	run.Event = hooks.EventPostAdd
+	run.Dir = absPath
+	if err := runHooks(out, cfg, run); err != nil {
+		return fmt.Errorf("worktree created at %s, but %w", displayPath(path), err)
+	}
+	return nil
+}
+
+// remoteBranchToTrack finds the remote branch to create a local tracking
+// branch for, or nil when name is not a remote branch. A plain branch name
+// is only tracked with --track or --remote; several candidates are offered
+// for choice.
+func remoteBranchToTrack(cmd *cobra.Command, name string) (*wt.RemoteBranch, error) {
+	found, err := wt.FindRemoteBranches(name)
+	if err != nil {
+		return nil, err
+	}
+	if addRemote != "" {
+		var kept []wt.RemoteBranch
+		for _, rb := range found {
+			if rb.Remote == addRemote {
+				kept = append(kept, rb)
+			}
+		}
+		if len(kept) == 0 {
+			return nil, fmt.Errorf("branch %q does not exist on remote %s (fetch it first: git fetch %s)", name, addRemote, addRemote)
+		}
+		found = kept
+	}
+
+	var refs []string
+	for _, rb := range found {
+		refs = append(refs, rb.Ref())
+	}
+	switch {
+	case len(found) == 0:
+		return nil, nil
+	case found[0].Branch == name && !addTrack && addRemote == "":
+		return nil, fmt.Errorf("branch %q exists only on the remote (%s).\nTo create a local branch tracking it: gitwo add %s --track", name, strings.Join(refs, ", "), name)
+	case len(found) == 1:
+		return &found[0], nil
+	}
+
+	if in := cmd.InOrStdin(); in == os.Stdin && !isTerminal(os.Stdin) {
+		return nil, fmt.Errorf("branch %q exists on several remotes (%s); choose one with --remote", name, strings.Join(refs, ", "))
+	}
+	fmt.Fprintf(cmd.ErrOrStderr(), "Branch %q exists on several remotes:\n", name)
+	i, err := pickPrompt(cmd.InOrStdin(), cmd.ErrOrStderr(), refs)
+	if err != nil {
+		return nil, err
+	}
+	return &found[i], nil
 }
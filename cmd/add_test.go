@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"bytes"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gitwohq/gitwo/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddCommand_TracksRemoteBranch(t *testing.T) {
+	repo := newTestRepo(t)
+	cfg := config.DefaultConfig()
+	cfg.WorktreesDir = "../trees"
+	cfg.NameTemplate = "${BRANCH}"
+	require.NoError(t, config.SaveConfig(repo, cfg))
+	t.Cleanup(func() {
+		addTrack, addRemote = false, ""
+		rootCmd.SetIn(nil)
+		rootCmd.SetErr(nil)
+	})
+
+	git := func(args ...string) string {
+		t.Helper()
+		out, err := exec.Command("git", append([]string{"-C", repo}, args...)...).CombinedOutput()
+		require.NoError(t, err, string(out))
+		return strings.TrimSpace(string(out))
+	}
+	parent := filepath.Dir(repo)
+	for _, name := range []string{"origin", "upstream"} {
+		remote := filepath.Join(parent, name+".git")
+		git("init", "-q", "--bare", remote)
+		git("remote", "add", name, remote)
+		git("push", "-q", name, "main:their-branch")
+	}
+	git("push", "-q", "origin", "main:colleague")
+	git("fetch", "-q", "--all")
+
+	t.Run("remote-qualified name", func(t *testing.T) {
+		rootCmd.SetArgs([]string{"add", "origin/colleague"})
+		require.NoError(t, rootCmd.Execute())
+		path := filepath.Join(parent, "trees", "colleague")
+		assert.DirExists(t, path)
+		assert.Equal(t, "origin/colleague", git("rev-parse", "--abbrev-ref", "colleague@{upstream}"))
+	})
+
+	t.Run("plain name needs --track", func(t *testing.T) {
+		rootCmd.SetArgs([]string{"add", "their-branch"})
+		assert.ErrorContains(t, rootCmd.Execute(), "--track")
+	})
+
+	t.Run("several remotes ask which one", func(t *testing.T) {
+		var stderr bytes.Buffer
+		rootCmd.SetErr(&stderr)
+		rootCmd.SetIn(strings.NewReader("2\n"))
+		rootCmd.SetArgs([]string{"add", "their-branch", "--track"})
+		require.NoError(t, rootCmd.Execute())
+		assert.Contains(t, stderr.String(), "upstream/their-branch")
+		assert.Equal(t, "upstream/their-branch", git("rev-parse", "--abbrev-ref", "their-branch@{upstream}"))
+		assert.DirExists(t, filepath.Join(parent, "trees", "their-branch"))
+	})
+}
+
+func TestAddCommand_Detach(t *testing.T) {
+	repo := newTestRepo(t)
+	cfg := config.DefaultConfig()
+	cfg.WorktreesDir = "../trees"
+	cfg.NameTemplate = "${BRANCH}"
+	require.NoError(t, config.SaveConfig(repo, cfg))
+	gitOutput(t, repo, "tag", "v1.0")
+	head := gitOutput(t, repo, "rev-parse", "HEAD")
+
+	var buf bytes.Buffer
+	rootCmd.SetOut(&buf)
+	t.Cleanup(func() {
+		addDetach, addTrack = false, false
+		rootCmd.SetOut(nil)
+	})
+
+	rootCmd.SetArgs([]string{"add", "--detach", "v1.0"})
+	require.NoError(t, rootCmd.Execute())
+	path := filepath.Join(filepath.Dir(repo), "trees", "v1.0")
+	assert.DirExists(t, path)
+	assert.Equal(t, strings.TrimSpace(head), strings.TrimSpace(gitOutput(t, path, "rev-parse", "HEAD")))
+	_, err := exec.Command("git", "-C", path, "symbolic-ref", "-q", "HEAD").Output()
+	assert.Error(t, err, "HEAD should be detached")
+
+	buf.Reset()
+	rootCmd.SetArgs([]string{"list"})
+	require.NoError(t, rootCmd.Execute())
+	assert.Contains(t, buf.String(), "(v1.0)")
+
+	rootCmd.SetArgs([]string{"add", "--detach", "v1.0", "--track"})
+	assert.ErrorContains(t, rootCmd.Execute(), "--detach")
+}
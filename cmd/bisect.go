@@ -0,0 +1,167 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/gitwohq/gitwo/internal/hooks"
+	"github.com/gitwohq/gitwo/internal/wt"
+	"github.com/spf13/cobra"
+)
+
+var (
+	bisectGood []string
+	bisectBad  string
+	bisectJobs int
+)
+
+func init() {
+	bisectCmd := &cobra.Command{
+		Use:   "bisect --good <rev> [--bad <rev>] [-j N] -- <command> [args...]",
+		Short: "Find the commit that broke a test, testing several commits at once",
+		Long: `Find the first bad commit between --good and --bad (default HEAD) by running
+<command> in N temporary worktrees in parallel.
+
+Each round tests N commits spread evenly over the remaining range, so the
+range shrinks about N+1 times per round instead of halving. The command's
+exit code is read like 'git bisect run' does: 0 is good, 125 means the commit
+cannot be tested (skip), 1-127 is bad, and anything else aborts the bisect.
+A single argument is run through 'sh -c'; a relative command runs from the
+tested commit, in the root of the worktree.
+
+The search follows the first-parent history of --bad, so on a branch with
+merges the culprit may be a merge commit. The worktrees are created in
+.gitwo/tmp, run the post_add hooks once each (e.g. to install dependencies)
+and are removed when bisect finishes or is interrupted.
+
+Examples:
+  gitwo bisect --good v1.2 -- ./run-test.sh
+  gitwo bisect --good v1.2 --bad HEAD -j 4 -- 'go test ./internal/...'`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := cmd.OutOrStdout()
+			if bisectJobs < 1 {
+				return fmt.Errorf("-j must be at least 1")
+			}
+			repoPath, err := wt.MainRoot()
+			if err != nil {
+				return err
+			}
+			cfg := loadRepoConfig(cmd.ErrOrStderr(), repoPath)
+
+			commits, err := wt.BisectRange(bisectBad, bisectGood)
+			if err != nil {
+				return err
+			}
+			search := wt.NewBisectSearch(commits)
+			jobs := min(bisectJobs, max(search.Remaining(), 1))
+			fmt.Fprintf(out, "Bisecting %d commit(s) with %d worktree(s)\n", len(commits), jobs)
+
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			// Remove the worktrees however bisect ends, interrupts included
+			var paths []string
+			defer func() {
+				for _, p := range paths {
+					if rmErr := wt.RemoveBisectWorktree(p); rmErr != nil {
+						fmt.Fprintf(cmd.ErrOrStderr(), "warning: %v; gitwo gc removes %s later\n", rmErr, displayPath(p))
+					}
+				}
+			}()
+			bad := commits[len(commits)-1]
+			for i := 0; i < jobs && ctx.Err() == nil && !search.Done(); i++ {
+				path, err := wt.AddBisectWorktree(bad)
+				if err != nil {
+					return err
+				}
+				paths = append(paths, path)
+				run := hookRun{
+					Event:        hooks.EventPostAdd,
+					Action:       "bisect",
+					RepoPath:     repoPath,
+					WorktreePath: path,
+					Dir:          path,
+				}
+				if err := runHooks(out, cfg, run); err != nil {
+					return err
+				}
+			}
+
+			for round := 1; !search.Done(); round++ {
+				if ctx.Err() != nil {
+					return fmt.Errorf("bisect interrupted")
+				}
+				batch := search.Next(len(paths))
+				fmt.Fprintf(out, "Round %d: testing %d of %d remaining commit(s)\n", round, len(batch), search.Remaining())
+
+				results := make([]execResult, len(batch))
+				logs := make([]bytes.Buffer, len(batch))
+				wt.ForEach(len(batch), len(batch), func(i int) {
+					path := paths[i]
+					if err := wt.CheckoutForBisect(path, batch[i]); err != nil {
+						results[i] = execResult{Status: "error", ExitCode: -1, Err: err}
+						return
+					}
+					env := hooks.CreateActionEnvironment("bisect", repoPath, "", path, cfg.HookVars())
+					results[i] = runInWorktree(ctx, wt.WorktreeItem{Path: path}, args, env, &logs[i])
+				})
+
+				for i, r := range results {
+					if r.Status == "cancelled" {
+						return fmt.Errorf("bisect interrupted")
+					}
+					v, ok := bisectVerdict(r)
+					if !ok {
+						io.Copy(cmd.ErrOrStderr(), &logs[i])
+						if r.Err != nil {
+							return fmt.Errorf("cannot test %s: %w", wt.ShortSHA(batch[i]), r.Err)
+						}
+						return fmt.Errorf("cannot test %s: the command exited with %d; use 125 to skip a commit", wt.ShortSHA(batch[i]), r.ExitCode)
+					}
+					search.Mark(batch[i], v)
+					fmt.Fprintf(out, "  %s  %-4s  %6s  %s\n", wt.ShortSHA(batch[i]), v, r.Duration.Round(100*time.Millisecond), wt.CommitSubject(batch[i]))
+				}
+			}
+
+			first, suspects := search.Result()
+			if first == "" {
+				fmt.Fprintln(out, "Could not tell which commit is the first bad one because commits were skipped. It is one of:")
+				for _, c := range suspects {
+					fmt.Fprintf(out, "  %s  %s\n", c, wt.CommitSubject(c))
+				}
+				return nil
+			}
+			fmt.Fprintf(out, "First bad commit: %s\n  %s\n", first, wt.CommitSubject(first))
+			return nil
+		},
+	}
+
+	bisectCmd.Flags().StringArrayVar(&bisectGood, "good", nil, "a revision known to be good (repeatable)")
+	bisectCmd.Flags().StringVar(&bisectBad, "bad", "HEAD", "a revision known to be bad")
+	bisectCmd.Flags().IntVarP(&bisectJobs, "jobs", "j", 4, "number of worktrees testing commits in parallel")
+	bisectCmd.Flags().BoolVar(&noHooks, "no-hooks", false, "skip the post_add hooks for the temporary worktrees")
+	_ = bisectCmd.MarkFlagRequired("good")
+	rootCmd.AddCommand(bisectCmd)
+}
+
+// bisectVerdict maps the exit status of a test run the way git bisect run
+// does. ok is false when the run should abort the bisect.
+func bisectVerdict(r execResult) (wt.BisectVerdict, bool) {
+	switch {
+	case r.Status == "ok":
+		return wt.BisectGood, true
+	case r.Status != "failed":
+		return 0, false
+	case r.ExitCode == 125:
+		return wt.BisectSkip, true
+	case r.ExitCode >= 1 && r.ExitCode < 128:
+		return wt.BisectBad, true
+	}
+	return 0, false
+}
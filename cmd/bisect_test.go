@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gitwohq/gitwo/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBisectCommand(t *testing.T) {
+	repo := newTestRepo(t)
+	cfg := config.DefaultConfig()
+	cfg.Hooks.PostAdd = []config.Hook{{Type: "command", Command: "touch .setup-done", Description: "Set up"}}
+	require.NoError(t, config.SaveConfig(repo, cfg))
+	gitOutput(t, repo, "add", ".gitwo")
+	gitOutput(t, repo, "commit", "-q", "-m", "configure gitwo")
+	gitOutput(t, repo, "tag", "v1.2")
+
+	var culprit string
+	for i := 1; i <= 12; i++ {
+		name := fmt.Sprintf("file%02d.txt", i)
+		if i == 8 {
+			name = "broken"
+		}
+		require.NoError(t, os.WriteFile(filepath.Join(repo, name), []byte("x\n"), 0o644))
+		gitOutput(t, repo, "add", name)
+		gitOutput(t, repo, "commit", "-q", "-m", "commit "+name)
+		if i == 8 {
+			culprit = strings.TrimSpace(gitOutput(t, repo, "rev-parse", "HEAD"))
+		}
+	}
+
+	var buf bytes.Buffer
+	rootCmd.SetOut(&buf)
+	t.Cleanup(func() {
+		rootCmd.SetOut(nil)
+		bisectGood, bisectBad, bisectJobs = nil, "HEAD", 4
+	})
+
+	// The post_add hook runs in every worktree before the tests
+	rootCmd.SetArgs([]string{"bisect", "--good", "v1.2", "-j", "3", "--", "test -e .setup-done && test ! -e broken"})
+	require.NoError(t, rootCmd.Execute())
+	assert.Contains(t, buf.String(), "with 3 worktree(s)")
+	assert.Contains(t, buf.String(), "First bad commit: "+culprit+"\n  commit broken")
+	assert.Equal(t, 1, strings.Count(gitOutput(t, repo, "worktree", "list", "--porcelain"), "worktree "), "temporary worktrees are removed")
+
+	t.Run("an exit code above 127 aborts", func(t *testing.T) {
+		rootCmd.SetArgs([]string{"bisect", "--good", "v1.2", "--", "exit 129"})
+		assert.ErrorContains(t, rootCmd.Execute(), "exited with 129")
+		assert.Equal(t, 1, strings.Count(gitOutput(t, repo, "worktree", "list", "--porcelain"), "worktree "))
+	})
+}
@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/gitwohq/gitwo/internal/wt"
+)
+
+// carriedChanges is a stash on its way into a new worktree: the current
+// worktree's changes for --carry, or an existing entry for --from-stash
+type carriedChanges struct {
+	source string // worktree the changes were taken from; "" for --from-stash
+	sha    string // the stash commit
+	label  string // how to refer to the changes in messages
+}
+
+// prepareCarry stashes the current worktree's changes for --carry or looks
+// up the --from-stash entry. It returns nil when there is nothing to bring.
+func prepareCarry(out io.Writer, branch string) (*carriedChanges, error) {
+	switch {
+	case newCarry:
+		items, err := wt.List()
+		if err != nil {
+			return nil, err
+		}
+		cur := wt.CurrentIndex(items)
+		if cur < 0 || items[cur].Bare {
+			return nil, fmt.Errorf("--carry takes the changes of the current worktree; run it inside one")
+		}
+		src := items[cur].Path
+		sha, err := wt.CarryOut(src, newUntracked, "gitwo: carry to "+branch)
+		if err != nil {
+			return nil, err
+		}
+		if sha == "" {
+			fmt.Fprintf(out, "note: no uncommitted changes to carry in %s\n", displayPath(src))
+			return nil, nil
+		}
+		return &carriedChanges{source: src, sha: sha, label: "uncommitted changes from " + displayPath(src)}, nil
+	case newFromStash != "":
+		sha, err := wt.ResolveStash(".", newFromStash)
+		if err != nil {
+			return nil, err
+		}
+		return &carriedChanges{sha: sha, label: fmt.Sprintf("%s (%s)", newFromStash, wt.ShortSHA(sha))}, nil
+	}
+	return nil, nil
+}
+
+// apply brings the changes into the worktree at path. The stash is dropped
+// only when they applied cleanly; otherwise it is kept and the conflict
+// reported, without failing the command.
+func (c *carriedChanges) apply(out io.Writer, path string) {
+	if c == nil {
+		return
+	}
+	restaged, err := wt.ApplyStash(path, c.sha)
+	if err != nil {
+		fmt.Fprintf(out, "warning: %v\n", err)
+		return
+	}
+	fmt.Fprintf(out, "Applied %s\n", c.label)
+	if !restaged {
+		fmt.Fprintln(out, "note: the staged changes could not be staged again; they are unstaged changes now")
+	}
+	if err := wt.DropStash(path, c.sha); err != nil {
+		fmt.Fprintf(out, "warning: %v\n", err)
+	}
+}
+
+// restore puts carried changes back where they came from after the new
+// worktree could not be created. Changes from --from-stash stay in the stash.
+func (c *carriedChanges) restore(out io.Writer) {
+	if c == nil || c.source == "" {
+		return
+	}
+	if _, err := wt.ApplyStash(c.source, c.sha); err != nil {
+		fmt.Fprintf(out, "warning: could not restore your changes in %s: %v\n", displayPath(c.source), err)
+		return
+	}
+	if err := wt.DropStash(c.source, c.sha); err != nil {
+		fmt.Fprintf(out, "warning: %v\n", err)
+	}
+}
@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gitwohq/gitwo/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func gitOutput(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput()
+	require.NoError(t, err, string(out))
+	return string(out)
+}
+
+func newCarryRepo(t *testing.T) (repo string, buf *bytes.Buffer) {
+	t.Helper()
+	repo = newTestRepo(t)
+	cfg := config.DefaultConfig()
+	cfg.WorktreesDir = "../trees"
+	cfg.NameTemplate = "${NAME}"
+	require.NoError(t, config.SaveConfig(repo, cfg))
+	gitOutput(t, repo, "add", ".gitwo")
+	gitOutput(t, repo, "commit", "-q", "-m", "gitwo config")
+
+	buf = &bytes.Buffer{}
+	rootCmd.SetOut(buf)
+	t.Cleanup(func() {
+		rootCmd.SetOut(nil)
+		newCarry = false
+		newUntracked = false
+		newFromStash = ""
+		newStartRef = ""
+	})
+	return repo, buf
+}
+
+func TestNewCommand_Carry(t *testing.T) {
+	repo, buf := newCarryRepo(t)
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "README.md"), []byte("# changed\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "staged.txt"), []byte("staged\n"), 0o644))
+	gitOutput(t, repo, "add", "staged.txt")
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "notes.txt"), []byte("untracked\n"), 0o644))
+	statusBefore := gitOutput(t, repo, "status", "--porcelain")
+
+	rootCmd.SetArgs([]string{"new", "login", "--carry", "--include-untracked"})
+	require.NoError(t, rootCmd.Execute())
+
+	path := filepath.Join(filepath.Dir(repo), "trees", "login")
+	assert.Equal(t, statusBefore, gitOutput(t, path, "status", "--porcelain"))
+	assert.Empty(t, gitOutput(t, repo, "status", "--porcelain"))
+	assert.Empty(t, gitOutput(t, repo, "stash", "list"))
+	assert.Contains(t, buf.String(), "Applied uncommitted changes from")
+}
+
+func TestNewCommand_FromStashConflictKeepsStash(t *testing.T) {
+	repo, buf := newCarryRepo(t)
+	gitOutput(t, repo, "branch", "base")
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "README.md"), []byte("# stashed\n"), 0o644))
+	gitOutput(t, repo, "stash", "-q")
+	stash := strings.TrimSpace(gitOutput(t, repo, "rev-parse", "stash@{0}"))
+
+	// The new branch starts from a commit that changed the same line
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "README.md"), []byte("# committed\n"), 0o644))
+	gitOutput(t, repo, "commit", "-q", "-am", "change readme")
+
+	rootCmd.SetArgs([]string{"new", "spike", "--from-stash", "stash@{0}"})
+	require.NoError(t, rootCmd.Execute())
+	assert.Contains(t, buf.String(), "did not apply cleanly")
+	assert.Equal(t, stash, strings.TrimSpace(gitOutput(t, repo, "rev-parse", "stash@{0}")))
+
+	rootCmd.SetArgs([]string{"new", "retry", "--from-stash", "stash@{0}", "--start-point", "base"})
+	require.NoError(t, rootCmd.Execute())
+	path := filepath.Join(filepath.Dir(repo), "trees", "retry")
+	content, err := os.ReadFile(filepath.Join(path, "README.md"))
+	require.NoError(t, err)
+	assert.Equal(t, "# stashed\n", string(content))
+	assert.Empty(t, gitOutput(t, repo, "stash", "list"))
+}
+
+func TestNewCommand_CarryFlagsValidated(t *testing.T) {
+	newCarryRepo(t)
+
+	rootCmd.SetArgs([]string{"new", "x", "--include-untracked"})
+	assert.ErrorContains(t, rootCmd.Execute(), "requires --carry")
+	newUntracked = false
+
+	rootCmd.SetArgs([]string{"new", "x", "--carry", "--from-stash", "stash@{0}"})
+	assert.ErrorContains(t, rootCmd.Execute(), "mutually exclusive")
+}
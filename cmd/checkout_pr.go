@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/gitwohq/gitwo/internal/gitutil"
+	"github.com/gitwohq/gitwo/internal/hooks"
+	"github.com/gitwohq/gitwo/internal/pathtmpl"
+	"github.com/gitwohq/gitwo/internal/wt"
+	"github.com/spf13/cobra"
+)
+
+var (
+	prRemote string
+	prForce  bool
+)
+
+func init() {
+	prCmd := &cobra.Command{
+		Use:     "checkout-pr <number>",
+		Aliases: []string{"pr"},
+		Short:   "Create a worktree for a pull/merge request",
+		Long: `Fetch a pull request (refs/pull/<n>/head on GitHub) or merge request
+(refs/merge-requests/<n>/head on GitLab) into a local pr/<n> branch and attach a
+worktree, placed like 'gitwo new' places worktrees.
+
+gitwo remembers the request number, so running the command again refreshes
+the existing worktree instead: the new head is fetched and the worktree reset
+to it when it is clean and has no commits of its own (--force resets anyway).
+
+Examples:
+  gitwo checkout-pr 123
+  gitwo checkout-pr 123 --remote upstream`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			number, err := strconv.Atoi(args[0])
+			if err != nil || number <= 0 {
+				return fmt.Errorf("%q is not a pull request number", args[0])
+			}
+			out := humanOut(cmd)
+
+			repoPath, err := wt.MainRoot()
+			if err != nil {
+				return err
+			}
+			cfg := loadRepoConfig(cmd.ErrOrStderr(), repoPath)
+
+			ref, head, err := wt.FetchPR(prRemote, number)
+			if err != nil {
+				return err
+			}
+
+			// Known request: refresh its worktree
+			item, meta, err := wt.FindPRWorktree(prRemote, number)
+			if err != nil {
+				return err
+			}
+			if item != nil {
+				changed, err := wt.RefreshPR(*item, *meta, head, prForce)
+				if err != nil {
+					return err
+				}
+				if changed {
+					fmt.Fprintf(out, "Updated %s to %s at %s\n", item.Label(), wt.ShortSHA(head), displayPath(item.Path))
+				} else {
+					fmt.Fprintf(out, "%s is up to date at %s\n", item.Label(), displayPath(item.Path))
+				}
+				if err := wt.UpdateMeta(item.Path, func(m *wt.Meta) { m.PR.Head, m.PR.Ref = head, ref }); err != nil {
+					fmt.Fprintf(cmd.ErrOrStderr(), "warning: failed to update gitwo metadata: %v\n", err)
+				}
+				emitCD(cmd, item.Path)
+				return nil
+			}
+
+			branch := wt.PRBranch(number)
+			if gitutil.BranchExists(branch) {
+				return fmt.Errorf("branch %s already exists but was not created by gitwo checkout-pr\ndelete it (git branch -D %s) or check it out with 'gitwo add %s'", branch, branch, branch)
+			}
+
+			prefix, name := pathtmpl.SplitBranch(branch, "")
+			path, err := worktreeTarget(cfg, repoPath, "", pathtmpl.Vars{Branch: branch, Name: name, Prefix: prefix})
+			if err != nil {
+				return err
+			}
+			if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+				return fmt.Errorf("failed to create worktrees dir %q: %w", filepath.Dir(path), err)
+			}
+
+			run := hookRun{
+				Event:        hooks.EventPreAdd,
+				Action:       "checkout-pr",
+				RepoPath:     repoPath,
+				Branch:       branch,
+				WorktreePath: path,
+				Dir:          repoPath,
+			}
+			if err := runHooks(out, cfg, run); err != nil {
+				return err
+			}
+
+			if err := gitutil.GitWorktreeAdd("-b", branch, path, head); err != nil {
+				return err
+			}
+			// Without the metadata a second checkout-pr cannot refresh the worktree
+			if err := wt.WriteMeta(path, wt.Meta{
+				Created: time.Now(),
+				PR:      &wt.PRMeta{Number: number, Remote: prRemote, Ref: ref, Head: head},
+			}); err != nil {
+				fmt.Fprintf(cmd.ErrOrStderr(), "warning: failed to write gitwo metadata: %v\n", err)
+			}
+			fmt.Fprintf(out, "Checked out #%d (%s) as %s at %s\n", number, ref, branch, displayPath(path))
+
+			run.Event = hooks.EventPostAdd
+			run.Dir = path
+			if err := runHooks(out, cfg, run); err != nil {
+				return fmt.Errorf("worktree created at %s, but %w", displayPath(path), err)
+			}
+
+			emitCD(cmd, path)
+			return nil
+		},
+	}
+
+	prCmd.Flags().StringVar(&prRemote, "remote", "origin", "remote to fetch the request from")
+	prCmd.Flags().BoolVarP(&prForce, "force", "f", false, "reset an existing worktree even if it has local changes or commits")
+	prCmd.Flags().BoolVar(&noHooks, "no-hooks", false, "skip pre_add/post_add hooks")
+	prCmd.Flags().BoolVar(&shellMode, "shell", false, "print a cd command for the shell wrapper")
+	rootCmd.AddCommand(prCmd)
+}
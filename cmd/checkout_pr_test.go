@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gitwohq/gitwo/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckoutPRCommand_CreatesThenRefreshes(t *testing.T) {
+	repo := newTestRepo(t)
+	cfg := config.DefaultConfig()
+	cfg.WorktreesDir = ".gitwo/worktrees"
+	cfg.NameTemplate = "${BRANCH}"
+	require.NoError(t, config.SaveConfig(repo, cfg))
+
+	git := func(dir string, args ...string) string {
+		t.Helper()
+		out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput()
+		require.NoError(t, err, string(out))
+		return strings.TrimSpace(string(out))
+	}
+
+	// A colleague's fork pushes commits to refs/pull/42/head on the remote
+	parent := filepath.Dir(repo)
+	remote := filepath.Join(parent, "remote.git")
+	git(parent, "init", "-q", "--bare", remote)
+	git(repo, "remote", "add", "origin", remote)
+	fork := filepath.Join(parent, "fork")
+	git(parent, "clone", "-q", repo, fork)
+	git(fork, "commit", "-q", "--allow-empty", "-m", "First")
+	git(fork, "push", "-q", remote, "HEAD:refs/pull/42/head")
+
+	rootCmd.SetArgs([]string{"checkout-pr", "42"})
+	require.NoError(t, rootCmd.Execute())
+	path := filepath.Join(repo, ".gitwo", "worktrees", "pr-42")
+	require.DirExists(t, path)
+	assert.Equal(t, "pr/42", git(path, "branch", "--show-current"))
+	assert.Equal(t, git(fork, "rev-parse", "HEAD"), git(path, "rev-parse", "HEAD"))
+
+	git(fork, "commit", "-q", "--allow-empty", "-m", "Second")
+	git(fork, "push", "-q", "-f", remote, "HEAD:refs/pull/42/head")
+
+	rootCmd.SetArgs([]string{"checkout-pr", "42"})
+	require.NoError(t, rootCmd.Execute())
+	assert.Equal(t, git(fork, "rev-parse", "HEAD"), git(path, "rev-parse", "HEAD"))
+	assert.NoDirExists(t, path+"-2", "a second run reuses the worktree")
+}
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/gitwohq/gitwo/internal/config"
+	"github.com/gitwohq/gitwo/internal/wt"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	cloneCmd := &cobra.Command{
+		Use:   "clone <url> [dir]",
+		Short: "Clone a repository into a bare repo + worktrees layout",
+		Long: `Clone a repository so that all worktrees live side by side:
+
+  shop/.bare          the repository (a bare clone)
+  shop/.git           file pointing at .bare
+  shop/.gitwo/        gitwo config (worktrees_dir ".", name_template "${BRANCH}")
+  shop/main/          worktree for the default branch
+  shop/feature-x/     worktrees added later with gitwo new / add
+
+Remote branches are available as origin/* as in a normal clone. All gitwo
+commands work from anywhere inside the layout. With the shell wrapper your
+shell changes into the default branch's worktree.
+
+Examples:
+  gitwo clone git@github.com:acme/shop.git
+  gitwo clone https://github.com/acme/shop.git ~/src/shop`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := humanOut(cmd)
+			url := args[0]
+			dir := wt.CloneDir(url)
+			if len(args) == 2 {
+				dir = args[1]
+			}
+			if dir == "" {
+				return fmt.Errorf("cannot derive a directory name from %q; pass one: gitwo clone <url> <dir>", url)
+			}
+
+			res, err := wt.Clone(url, dir, cmd.ErrOrStderr())
+			if err != nil {
+				return err
+			}
+
+			cfg := config.DefaultConfig()
+			cfg.WorktreesDir = "."
+			cfg.NameTemplate = "${BRANCH}"
+			cfg.MainBranch = "origin/" + res.DefaultBranch
+			if err := config.SaveConfig(res.Root, cfg); err != nil {
+				return fmt.Errorf("cloned into %s, but %w", res.Root, err)
+			}
+			_ = wt.WriteMeta(res.Worktree, wt.Meta{Created: time.Now()})
+
+			fmt.Fprintf(out, "Cloned %s into %s (bare repository in %s)\n", url, displayPath(res.Root), filepath.Join(displayPath(res.Root), ".bare"))
+			fmt.Fprintf(out, "Worktree for %s at %s\n", res.DefaultBranch, displayPath(res.Worktree))
+			emitCD(cmd, res.Worktree)
+			return nil
+		},
+	}
+
+	cloneCmd.Flags().BoolVar(&shellMode, "shell", false, "print a cd command for the shell wrapper")
+	rootCmd.AddCommand(cloneCmd)
+}
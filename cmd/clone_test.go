@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCloneCommand_WorktreesSideBySide(t *testing.T) {
+	src := newTestRepo(t)
+	parent := filepath.Dir(src)
+	t.Chdir(parent)
+
+	rootCmd.SetArgs([]string{"clone", "file://" + src, "store"})
+	require.NoError(t, rootCmd.Execute())
+	root := filepath.Join(parent, "store")
+	require.DirExists(t, filepath.Join(root, "main"))
+	assert.FileExists(t, filepath.Join(root, ".gitwo", "config.yml"))
+
+	t.Chdir(root)
+	rootCmd.SetArgs([]string{"new", "login"})
+	require.NoError(t, rootCmd.Execute())
+
+	path := filepath.Join(root, "feature-login")
+	require.DirExists(t, path)
+	out, err := exec.Command("git", "-C", path, "branch", "--show-current").Output()
+	require.NoError(t, err)
+	assert.Equal(t, "feature/login", strings.TrimSpace(string(out)))
+}
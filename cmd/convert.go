@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gitwohq/gitwo/internal/config"
+	"github.com/gitwohq/gitwo/internal/wt"
+	"github.com/spf13/cobra"
+)
+
+var convertDryRun bool
+
+func init() {
+	convertCmd := &cobra.Command{
+		Use:   "convert",
+		Short: "Convert a normal clone into the bare repo + worktrees layout",
+		Long: `Convert the current clone in place into the layout 'gitwo clone' creates:
+
+  shop/.git       ->  shop/.bare      the repository, now bare
+                      shop/.git       file pointing at .bare
+  shop/<files>    ->  shop/main/      worktree for the checked-out branch
+
+Local branches, stashes, config and hooks move along with the repository. The
+checkout keeps its index and working files, so staged, unstaged and untracked
+changes are still there afterwards. Linked worktrees are repaired to point at
+the new location. .gitwo/config.yml is updated so new worktrees go next to
+the converted one.
+
+If any step fails, the steps done so far are undone and the original layout is
+restored. Use --dry-run to see the plan first.
+
+Examples:
+  gitwo convert --dry-run
+  gitwo convert`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := humanOut(cmd)
+
+			plan, err := wt.PlanConvert()
+			if err != nil {
+				return err
+			}
+			if convertDryRun {
+				fmt.Fprintf(out, "Would convert %s:\n", displayPath(plan.Root))
+				for i, step := range plan.Steps() {
+					fmt.Fprintf(out, "  %d. %s\n", i+1, step)
+				}
+				fmt.Fprintf(out, "  %d. set worktrees_dir \".\" and name_template \"${BRANCH}\" in %s\n",
+					len(plan.Steps())+1, filepath.Join(displayPath(plan.Root), ".gitwo", "config.yml"))
+				return nil
+			}
+
+			// The current directory may be about to move
+			cwd, err := os.Getwd()
+			if err != nil {
+				return err
+			}
+			follow := plan.NewPath(cwd)
+			inRoot := cwdInside(plan.Root)
+			if err := os.Chdir(plan.Root); err != nil {
+				return err
+			}
+
+			cfg := loadRepoConfig(out, plan.Root)
+			if err := wt.Convert(plan); err != nil {
+				return err
+			}
+			fmt.Fprintf(out, "Converted %s (bare repository in %s)\n", displayPath(plan.Root), filepath.Join(displayPath(plan.Root), ".bare"))
+			fmt.Fprintf(out, "Worktree for %s at %s\n", plan.Branch, displayPath(plan.Worktree))
+
+			// Only touch the layout keys of a config that stayed in the root;
+			// write the loaded one when it moved with the checkout or is missing
+			err = config.UpdateConfig(plan.Root, map[string]string{"worktrees_dir": ".", "name_template": "${BRANCH}"})
+			if errors.Is(err, os.ErrNotExist) {
+				cfg.WorktreesDir = "."
+				cfg.NameTemplate = "${BRANCH}"
+				err = config.SaveConfig(plan.Root, cfg)
+			}
+			if err != nil {
+				fmt.Fprintf(out, "warning: failed to update .gitwo/config.yml: %v\n", err)
+			}
+			_ = wt.WriteMeta(plan.Worktree, wt.Meta{Created: time.Now()})
+
+			// A directory in the root that stays put, or the root itself
+			if follow == cwd && inRoot {
+				follow = plan.Worktree
+			}
+			if shellMode {
+				emitCD(cmd, follow)
+			} else {
+				fmt.Fprintf(out, "Continue working there: cd %s\n", follow)
+			}
+			return nil
+		},
+	}
+
+	convertCmd.Flags().BoolVarP(&convertDryRun, "dry-run", "n", false, "Print the plan without changing anything")
+	convertCmd.Flags().BoolVar(&shellMode, "shell", false, "print a cd command for the shell wrapper")
+	rootCmd.AddCommand(convertCmd)
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertCommand(t *testing.T) {
+	repo := newTestRepo(t)
+	require.NoError(t, os.MkdirAll(filepath.Join(repo, "docs"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "docs", "notes.md"), []byte("wip\n"), 0o644))
+	// An untracked config stays in the root and keeps its other settings
+	require.NoError(t, os.MkdirAll(filepath.Join(repo, ".gitwo"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(repo, ".gitwo", "config.yml"), []byte("# team settings\nauto_switch: true\n"), 0o644))
+	t.Chdir(filepath.Join(repo, "docs"))
+
+	var stdout, stderr bytes.Buffer
+	rootCmd.SetOut(&stdout)
+	rootCmd.SetErr(&stderr)
+	t.Cleanup(func() {
+		rootCmd.SetOut(nil)
+		rootCmd.SetErr(nil)
+		shellMode = false
+		convertDryRun = false
+	})
+
+	t.Run("dry run changes nothing", func(t *testing.T) {
+		rootCmd.SetArgs([]string{"convert", "--dry-run"})
+		require.NoError(t, rootCmd.Execute())
+		assert.Contains(t, stdout.String(), "Would convert")
+		assert.Contains(t, stdout.String(), filepath.Join(repo, "main"))
+		fi, err := os.Stat(filepath.Join(repo, ".git"))
+		require.NoError(t, err)
+		assert.True(t, fi.IsDir())
+		assert.NoDirExists(t, filepath.Join(repo, ".bare"))
+	})
+	convertDryRun = false
+	stdout.Reset()
+
+	rootCmd.SetArgs([]string{"convert", "--shell"})
+	require.NoError(t, rootCmd.Execute())
+
+	wtPath := filepath.Join(repo, "main")
+	assert.FileExists(t, filepath.Join(wtPath, "docs", "notes.md"))
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	assert.Equal(t, "cd "+filepath.Join(wtPath, "docs"), lines[len(lines)-1])
+	assert.Contains(t, stderr.String(), "Converted")
+
+	cfg, err := os.ReadFile(filepath.Join(repo, ".gitwo", "config.yml"))
+	require.NoError(t, err)
+	assert.Contains(t, string(cfg), "worktrees_dir: .")
+	assert.Contains(t, string(cfg), "# team settings\nauto_switch: true\n")
+	assert.NotContains(t, string(cfg), "main_branch", "defaults are not written into the file")
+
+	// New worktrees go next to the converted one
+	t.Chdir(wtPath)
+	shellMode = false
+	rootCmd.SetArgs([]string{"new", "login"})
+	require.NoError(t, rootCmd.Execute())
+	out, err := exec.Command("git", "-C", filepath.Join(repo, "feature-login"), "branch", "--show-current").Output()
+	require.NoError(t, err)
+	assert.Equal(t, "feature/login", strings.TrimSpace(string(out)))
+}
+
+func TestConvertCommand_FromLinkedWorktree(t *testing.T) {
+	repo := newTestRepo(t)
+	linked := filepath.Join(filepath.Dir(repo), "hotfix")
+	out, err := exec.Command("git", "-C", repo, "worktree", "add", "-q", "-b", "hotfix", linked).CombinedOutput()
+	require.NoError(t, err, string(out))
+	t.Chdir(linked)
+
+	var stdout bytes.Buffer
+	rootCmd.SetOut(&stdout)
+	rootCmd.SetErr(&bytes.Buffer{})
+	t.Cleanup(func() {
+		rootCmd.SetOut(nil)
+		rootCmd.SetErr(nil)
+		shellMode = false
+	})
+
+	rootCmd.SetArgs([]string{"convert", "--shell"})
+	require.NoError(t, rootCmd.Execute())
+	assert.FileExists(t, filepath.Join(repo, "main", "README.md"))
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	assert.Equal(t, "cd "+linked, lines[len(lines)-1], "a worktree outside the root stays where it is")
+}
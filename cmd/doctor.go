@@ -0,0 +1,256 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/gitwohq/gitwo/internal/config"
+	"github.com/gitwohq/gitwo/internal/pathtmpl"
+	"github.com/gitwohq/gitwo/internal/shell"
+	"github.com/gitwohq/gitwo/internal/wt"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	doctorFix  bool
+	doctorJSON bool
+)
+
+// doctorSchemaVersion is bumped whenever fields are removed or change meaning
+// in the --json output
+const doctorSchemaVersion = 1
+
+// doctorEntry is the --json schema for one finding
+type doctorEntry struct {
+	Check    string `json:"check"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+	Fix      string `json:"fix,omitempty"`
+	Fixable  bool   `json:"fixable"`
+	Fixed    bool   `json:"fixed"`
+	FixError string `json:"fix_error,omitempty"`
+}
+
+func init() {
+	doctorCmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Diagnose git, shell wrapper, configuration and worktree problems",
+		Long: `Check the environment gitwo runs in and report each problem with its
+severity (info, warning, error) and a suggested fix:
+
+- the git version and which worktree features it supports
+- whether the shell wrapper is installed, loaded and up to date
+- whether .gitwo/config.yml parses and has no unknown keys, and whether
+  'gitwo config' reads the same auto_switch as the other commands
+- worktrees that git lists but whose directory is gone, broken .git links,
+  stale index.lock files and worktrees moved without gitwo
+- directories under the worktrees dir that are worktrees git no longer knows
+
+--fix applies the fixes that are safe to apply without asking (refreshing an
+outdated wrapper, git worktree repair/prune, removing stale locks). gitwo
+doctor exits non-zero when errors remain.
+
+Examples:
+  gitwo doctor
+  gitwo doctor --fix
+  gitwo doctor --json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			findings := wt.CheckGitVersion()
+			findings = append(findings, checkShellWrapper()...)
+
+			if root, err := wt.MainRoot(); err != nil {
+				findings = append(findings, wt.Finding{Check: "repository", Severity: wt.SeverityInfo, Message: "not in a git repository; repository checks skipped"})
+			} else {
+				cfg, cfgFindings := checkConfig(root)
+				findings = append(findings, cfgFindings...)
+				repoFindings, err := wt.CheckRepository(worktreeParents(cfg, root))
+				if err != nil {
+					return err
+				}
+				if len(repoFindings) == 0 {
+					repoFindings = []wt.Finding{{Check: "worktree", Severity: wt.SeverityOK, Message: "all worktrees are healthy"}}
+				}
+				findings = append(findings, repoFindings...)
+			}
+
+			entries := make([]doctorEntry, len(findings))
+			errorsLeft := 0
+			for i, f := range findings {
+				entries[i] = doctorEntry{Check: f.Check, Severity: string(f.Severity), Message: f.Message, Fix: f.Fix, Fixable: f.Apply != nil}
+				if doctorFix && f.Apply != nil {
+					if err := f.Apply(); err != nil {
+						entries[i].FixError = err.Error()
+					} else {
+						entries[i].Fixed = true
+					}
+				}
+				if f.Severity == wt.SeverityError && !entries[i].Fixed {
+					errorsLeft++
+				}
+			}
+
+			out := cmd.OutOrStdout()
+			if doctorJSON {
+				enc := json.NewEncoder(out)
+				enc.SetIndent("", "  ")
+				if err := enc.Encode(struct {
+					Version  int           `json:"version"`
+					Findings []doctorEntry `json:"findings"`
+				}{doctorSchemaVersion, entries}); err != nil {
+					return err
+				}
+			} else {
+				writeDoctorReport(out, entries)
+			}
+			if errorsLeft > 0 {
+				return fmt.Errorf("%d error(s) found", errorsLeft)
+			}
+			return nil
+		},
+	}
+
+	doctorCmd.Flags().BoolVar(&doctorFix, "fix", false, "apply the fixes that are safe to apply automatically")
+	doctorCmd.Flags().BoolVar(&doctorJSON, "json", false, "print the findings as JSON")
+	rootCmd.AddCommand(doctorCmd)
+}
+
+// writeDoctorReport prints one line per finding, followed by its fix
+func writeDoctorReport(out io.Writer, entries []doctorEntry) {
+	fixable, fixed := 0, 0
+	counts := map[string]int{}
+	for _, e := range entries {
+		if e.Fixed {
+			fixed++
+		} else {
+			counts[e.Severity]++
+		}
+		fmt.Fprintf(out, "%-9s %-10s %s\n", "["+e.Severity+"]", e.Check, e.Message)
+		switch {
+		case e.Fixed:
+			fmt.Fprintf(out, "%20s fixed: %s\n", "", e.Fix)
+		case e.FixError != "":
+			fmt.Fprintf(out, "%20s fix failed: %s\n", "", e.FixError)
+		case e.Fix != "":
+			fmt.Fprintf(out, "%20s fix: %s\n", "", e.Fix)
+			if e.Fixable {
+				fixable++
+			}
+		}
+	}
+	fmt.Fprintf(out, "\n%d error(s), %d warning(s), %d note(s)", counts["error"], counts["warning"], counts["info"])
+	if fixed > 0 {
+		fmt.Fprintf(out, ", %d fixed", fixed)
+	}
+	if fixable > 0 {
+		fmt.Fprintf(out, "; run gitwo doctor --fix to fix %d of them", fixable)
+	}
+	fmt.Fprintln(out)
+}
+
+// checkShellWrapper checks the wrapper in the profile of the detected shell
+func checkShellWrapper() []wt.Finding {
+	sh := shell.DetectShell()
+	profile := shell.GetShellProfilePath(sh)
+	switch {
+	case !shell.IsWrapperInstalled(sh):
+		return []wt.Finding{{Check: "shell", Severity: wt.SeverityWarning,
+			Message: fmt.Sprintf("the %s wrapper is not installed in %s, so gitwo cannot change your directory", sh, profile),
+			Fix:     "gitwo shell-install"}}
+	case !shell.IsWrapperUpToDate(sh):
+		return []wt.Finding{{Check: "shell", Severity: wt.SeverityWarning,
+			Message: fmt.Sprintf("the %s wrapper in %s is out of date", sh, profile),
+			Fix:     "gitwo shell-install",
+			Apply: func() error {
+				_, err := installWrapper(sh)
+				return err
+			}}}
+	case os.Getenv("GITWO_WRAPPER") == "":
+		return []wt.Finding{{Check: "shell", Severity: wt.SeverityInfo,
+			Message: fmt.Sprintf("the %s wrapper is installed but not loaded in this shell", sh),
+			Fix:     "restart your shell or reload " + profile}}
+	}
+	return []wt.Finding{{Check: "shell", Severity: wt.SeverityOK, Message: fmt.Sprintf("the %s wrapper is installed and up to date", sh)}}
+}
+
+var unknownKeyRe = regexp.MustCompile(`line (\d+): field (\S+) not found`)
+
+// checkConfig checks .gitwo/config.yml in the main worktree and compares it
+// with what the legacy `gitwo config` command reads. It returns the config
+// the other commands use.
+func checkConfig(root string) (*config.Config, []wt.Finding) {
+	path := filepath.Join(root, ".gitwo", "config.yml")
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return config.DefaultConfig(), []wt.Finding{{Check: "config", Severity: wt.SeverityInfo, Message: "no .gitwo/config.yml, using the defaults", Fix: "gitwo init"}}
+	}
+	cfg, err := config.LoadConfig(root)
+	if err != nil {
+		return cfg, []wt.Finding{{Check: "config", Severity: wt.SeverityError, Message: fmt.Sprintf("%s: %v", path, err), Fix: "correct the YAML in " + path}}
+	}
+
+	var findings []wt.Finding
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&config.Config{}); err != nil && err != io.EOF {
+		var keys []string
+		for _, m := range unknownKeyRe.FindAllStringSubmatch(err.Error(), -1) {
+			keys = append(keys, fmt.Sprintf("%s (line %s)", m[2], m[1]))
+		}
+		if len(keys) > 0 {
+			findings = append(findings, wt.Finding{Check: "config", Severity: wt.SeverityWarning,
+				Message: fmt.Sprintf("%s has unknown keys that gitwo ignores: %s", path, strings.Join(keys, ", ")),
+				Fix:     "fix the spelling or remove them"})
+		}
+	}
+	findings = append(findings, checkLegacyConfig(path, data, cfg)...)
+	if len(findings) == 0 {
+		findings = append(findings, wt.Finding{Check: "config", Severity: wt.SeverityOK, Message: ".gitwo/config.yml is valid"})
+	}
+	return cfg, findings
+}
+
+// checkLegacyConfig compares auto_switch as `gitwo config` reads it (from the
+// current worktree, without defaults) with the value the other commands use
+func checkLegacyConfig(path string, data []byte, cfg *config.Config) []wt.Finding {
+	legacy, err := wt.LoadConfig()
+	if err != nil || legacy.AutoSwitch == cfg.AutoSwitch {
+		return nil
+	}
+	legacyPath, _ := wt.ConfigPath()
+	if legacyPath != "" && filepath.Clean(legacyPath) != filepath.Clean(path) {
+		return []wt.Finding{{Check: "config", Severity: wt.SeverityWarning,
+			Message: fmt.Sprintf("'gitwo config' reads auto_switch: %t from %s, other commands read %t from %s", legacy.AutoSwitch, legacyPath, cfg.AutoSwitch, path),
+			Fix:     "set auto_switch to the same value in both files"}}
+	}
+	// A file without the key: the legacy reader has no defaults
+	var keys map[string]any
+	if yaml.Unmarshal(data, &keys) != nil {
+		return nil
+	}
+	if _, ok := keys["auto_switch"]; ok {
+		return nil
+	}
+	line := fmt.Sprintf("auto_switch: %t", cfg.AutoSwitch)
+	return []wt.Finding{{Check: "config", Severity: wt.SeverityWarning,
+		Message: fmt.Sprintf("%s does not set auto_switch: 'gitwo config' shows %t, other commands use the default %t", path, legacy.AutoSwitch, cfg.AutoSwitch),
+		Fix:     fmt.Sprintf("add %q to %s", line, path)}}
+}
+
+// worktreeParents returns the directories new worktrees are created in, to
+// look for leftover worktrees: the worktrees dir and the temporary worktrees
+func worktreeParents(cfg *config.Config, root string) []string {
+	parents := []string{filepath.Join(root, wt.EphemeralDir)}
+	if p, err := templatePath(cfg, root, "", pathtmpl.Vars{Branch: "x", Name: "x"}); err == nil {
+		parents = append(parents, filepath.Dir(p))
+	}
+	return parents
+}
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gitwohq/gitwo/internal/shell"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDoctorCommand(t *testing.T) {
+	repo := newTestRepo(t)
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("SHELL", "/bin/bash")
+	t.Setenv("GITWO_WRAPPER", "1")
+	t.Setenv("PSModulePath", "")
+
+	// An outdated wrapper, a config without auto_switch and a stale lock
+	require.NoError(t, os.WriteFile(filepath.Join(home, ".bashrc"),
+		[]byte(shell.WrapperBeginMarker+"\ngitwo() { :; }\n"+shell.WrapperEndMarker+"\n"), 0o644))
+	require.NoError(t, os.MkdirAll(filepath.Join(repo, ".gitwo"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(repo, ".gitwo", "config.yml"), []byte("worktrees_dir: ..\nworktree_dir: oops\n"), 0o644))
+	lock := filepath.Join(repo, ".git", "index.lock")
+	require.NoError(t, os.WriteFile(lock, nil, 0o644))
+	old := time.Now().Add(-time.Hour)
+	require.NoError(t, os.Chtimes(lock, old, old))
+
+	var buf bytes.Buffer
+	rootCmd.SetOut(&buf)
+	t.Cleanup(func() {
+		rootCmd.SetOut(nil)
+		doctorFix, doctorJSON = false, false
+	})
+
+	run := func(args ...string) map[string]doctorEntry {
+		t.Helper()
+		buf.Reset()
+		rootCmd.SetArgs(append([]string{"doctor", "--json"}, args...))
+		require.NoError(t, rootCmd.Execute())
+		var report struct {
+			Version  int           `json:"version"`
+			Findings []doctorEntry `json:"findings"`
+		}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &report), buf.String())
+		assert.Equal(t, 1, report.Version)
+		byCheck := map[string]doctorEntry{}
+		for _, f := range report.Findings {
+			if prev, ok := byCheck[f.Check]; !ok || prev.Severity == "ok" {
+				byCheck[f.Check] = f
+			}
+		}
+		return byCheck
+	}
+
+	findings := run()
+	assert.Equal(t, "ok", findings["git"].Severity)
+	assert.Equal(t, "warning", findings["shell"].Severity)
+	assert.True(t, findings["shell"].Fixable)
+	assert.Contains(t, findings["config"].Message, "worktree_dir (line 2)")
+	assert.Contains(t, findings["worktree"].Message, "stale index.lock")
+
+	findings = run("--fix")
+	assert.True(t, findings["shell"].Fixed)
+	assert.True(t, shell.IsWrapperUpToDate("bash"))
+	assert.NoFileExists(t, lock)
+	assert.False(t, findings["config"].Fixable)
+	data, err := os.ReadFile(filepath.Join(repo, ".gitwo", "config.yml"))
+	require.NoError(t, err)
+	assert.Equal(t, "worktrees_dir: ..\nworktree_dir: oops\n", string(data), "--fix leaves the config alone")
+
+	findings = run()
+	assert.Equal(t, "ok", findings["shell"].Severity)
+	assert.Equal(t, "ok", findings["worktree"].Severity)
+	assert.Equal(t, "warning", findings["config"].Severity, "unknown keys are not fixed automatically")
+}
@@ -0,0 +1,327 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/gitwohq/gitwo/internal/hooks"
+	"github.com/gitwohq/gitwo/internal/wt"
+	"github.com/spf13/cobra"
+)
+
+var (
+	execFilters  []string
+	execJobs     int
+	execGroup    bool
+	execFailFast bool
+	execTimeout  time.Duration
+)
+
+// execResult is the outcome of running the command in one worktree
+type execResult struct {
+	Item     wt.WorktreeItem
+	ExitCode int
+	Status   string // ok, failed, timeout, cancelled, error
+	Err      error
+	Duration time.Duration
+}
+
+func init() {
+	execCmd := &cobra.Command{
+		Use:     "exec [--filter ...] [-j N] -- <command> [args...]",
+		Aliases: []string{"foreach"},
+		Short:   "Run a command in every worktree",
+		Long: `Run a command in every worktree, in parallel.
+
+A single argument is run through 'sh -c' (so pipes and && work); several
+arguments are run directly. The command gets the same GITWO_* environment as
+hooks (GITWO_REPO, GITWO_BRANCH, GITWO_PATH, ...) with GITWO_ACTION=exec.
+
+Output lines are prefixed with the worktree name, coloured per worktree on a
+terminal. With --group each worktree's output is printed in one block when it
+finishes. A summary of exit codes follows; gitwo exits non-zero if any run
+failed.
+
+Filters (repeatable, all must match):
+  dirty | clean     worktrees with or without local changes
+  main | linked     the main worktree or the others
+  <glob>            branch or directory name, e.g. 'feature/*'
+
+Examples:
+  gitwo exec -- go test ./...
+  gitwo exec -j 2 --group -- make lint
+  gitwo foreach --filter 'feature/*' --fail-fast -- 'npm ci && npm test'
+  gitwo exec --timeout 5m -- go build ./...`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			repoPath, err := wt.MainRoot()
+			if err != nil {
+				return err
+			}
+			cfg := loadRepoConfig(cmd.ErrOrStderr(), repoPath)
+
+			items, err := wt.List()
+			if err != nil {
+				return err
+			}
+			items, err = filterWorktrees(items, execFilters)
+			if err != nil {
+				return err
+			}
+			if len(items) == 0 {
+				return fmt.Errorf("no worktrees match the filters")
+			}
+
+			out := cmd.OutOrStdout()
+			colour := useColour(out)
+			width := 0
+			for _, it := range items {
+				width = max(width, len(worktreeName(it)))
+			}
+
+			ctx, cancel := context.WithCancel(cmd.Context())
+			defer cancel()
+
+			var mu sync.Mutex
+			results := make([]execResult, len(items))
+			wt.ForEach(len(items), execJobs, func(i int) {
+				it := items[i]
+				if ctx.Err() != nil {
+					results[i] = execResult{Item: it, ExitCode: -1, Status: "cancelled"}
+					return
+				}
+
+				prefix := fmt.Sprintf("%-*s │ ", width, worktreeName(it))
+				if colour {
+					prefix = ansiColour(i, prefix)
+				}
+				var w io.Writer
+				var buf bytes.Buffer
+				if execGroup {
+					w = &buf
+				} else {
+					pw := &prefixWriter{mu: &mu, out: out, prefix: prefix}
+					defer pw.Flush()
+					w = pw
+				}
+
+				env := hooks.CreateActionEnvironment("exec", repoPath, it.Branch, it.Path, cfg.HookVars())
+				results[i] = runInWorktree(ctx, it, args, env, w)
+				if results[i].Status != "ok" && execFailFast {
+					cancel()
+				}
+
+				if execGroup {
+					mu.Lock()
+					fmt.Fprintf(out, "%s %s (%s)\n", ansiBold(colour, "==>"), displayPath(it.Path), it.Label())
+					out.Write(buf.Bytes())
+					if buf.Len() > 0 && !bytes.HasSuffix(buf.Bytes(), []byte("\n")) {
+						fmt.Fprintln(out)
+					}
+					mu.Unlock()
+				}
+			})
+
+			failed := writeExecSummary(out, results)
+			if failed > 0 {
+				return fmt.Errorf("command failed in %d of %d worktree(s)", failed, len(results))
+			}
+			return nil
+		},
+	}
+
+	execCmd.Flags().StringArrayVar(&execFilters, "filter", nil, "only run in matching worktrees: dirty, clean, main, linked or a branch/directory glob")
+	execCmd.Flags().IntVarP(&execJobs, "jobs", "j", 4, "number of worktrees to run in parallel")
+	execCmd.Flags().BoolVar(&execGroup, "group", false, "print each worktree's output in one block instead of streaming")
+	execCmd.Flags().BoolVar(&execFailFast, "fail-fast", false, "stop starting new runs and cancel running ones after the first failure")
+	execCmd.Flags().DurationVar(&execTimeout, "timeout", 0, "kill the command in a worktree after this long, e.g. 5m (0 disables)")
+	rootCmd.AddCommand(execCmd)
+}
+
+// runInWorktree runs args in the worktree, writing stdout and stderr to w
+func runInWorktree(ctx context.Context, it wt.WorktreeItem, args []string, env map[string]string, w io.Writer) execResult {
+	res := execResult{Item: it}
+	if execTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, execTimeout)
+		defer cancel()
+	}
+
+	var c *exec.Cmd
+	if len(args) == 1 {
+		c = exec.CommandContext(ctx, "sh", "-c", args[0])
+	} else {
+		c = exec.CommandContext(ctx, args[0], args[1:]...)
+	}
+	c.Dir = it.Path
+	c.Stdout, c.Stderr = w, w
+	c.WaitDelay = 2 * time.Second
+	c.Env = os.Environ()
+	for k, v := range env {
+		c.Env = append(c.Env, k+"="+v)
+	}
+
+	start := time.Now()
+	err := c.Run()
+	res.Duration = time.Since(start)
+
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+		res.Status = "ok"
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		res.Status, res.ExitCode = "timeout", -1
+	case ctx.Err() != nil:
+		res.Status, res.ExitCode = "cancelled", -1
+	case errors.As(err, &exitErr):
+		res.Status, res.ExitCode = "failed", exitErr.ExitCode()
+	default:
+		res.Status, res.ExitCode, res.Err = "error", -1, err
+	}
+	return res
+}
+
+// writeExecSummary prints one line per worktree and returns the number of
+// runs that did not succeed
+func writeExecSummary(out io.Writer, results []execResult) int {
+	failed := 0
+	fmt.Fprintln(out)
+	tw := tabwriter.NewWriter(out, 2, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "PATH\tBRANCH\tEXIT\tSTATUS\tTIME")
+	for _, r := range results {
+		if r.Status != "ok" {
+			failed++
+		}
+		exit := fmt.Sprint(r.ExitCode)
+		if r.ExitCode < 0 {
+			exit = "-"
+		}
+		status := r.Status
+		if r.Err != nil {
+			status += ": " + r.Err.Error()
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", displayPath(r.Item.Path), r.Item.Label(), exit, status, r.Duration.Round(time.Millisecond))
+	}
+	tw.Flush()
+	return failed
+}
+
+// filterWorktrees keeps the items matching all filters
+func filterWorktrees(items []wt.WorktreeItem, filters []string) ([]wt.WorktreeItem, error) {
+	var statuses []wt.Status
+	for _, f := range filters {
+		if f == "dirty" || f == "clean" {
+			statuses = wt.CollectStatus(items, "", 8)
+			break
+		}
+	}
+
+	var kept []wt.WorktreeItem
+	for i, it := range items {
+		if it.Bare {
+			continue
+		}
+		ok := true
+		for _, f := range filters {
+			switch f {
+			case "dirty":
+				ok = statuses[i].Err == nil && statuses[i].Dirty()
+			case "clean":
+				ok = statuses[i].Err == nil && !statuses[i].Dirty()
+			case "main":
+				ok = i == 0
+			case "linked":
+				ok = i != 0
+			default:
+				if _, err := filepath.Match(f, ""); err != nil {
+					return nil, fmt.Errorf("invalid --filter pattern %q: %w", f, err)
+				}
+				byBranch, _ := filepath.Match(f, it.Branch)
+				byName, _ := filepath.Match(f, filepath.Base(it.Path))
+				ok = byBranch || byName
+			}
+			if !ok {
+				break
+			}
+		}
+		if ok {
+			kept = append(kept, it)
+		}
+	}
+	return kept, nil
+}
+
+// worktreeName is the short label used to prefix output lines
+func worktreeName(it wt.WorktreeItem) string {
+	return filepath.Base(it.Path)
+}
+
+// prefixWriter writes complete lines to out, each starting with prefix.
+// Writers sharing mu never interleave within a line.
+type prefixWriter struct {
+	mu     *sync.Mutex
+	out    io.Writer
+	prefix string
+	buf    []byte
+}
+
+func (p *prefixWriter) Write(b []byte) (int, error) {
+	p.buf = append(p.buf, b...)
+	for {
+		i := bytes.IndexByte(p.buf, '\n')
+		if i < 0 {
+			break
+		}
+		p.writeLine(p.buf[:i+1])
+		p.buf = p.buf[i+1:]
+	}
+	return len(b), nil
+}
+
+// Flush writes a trailing partial line, if any
+func (p *prefixWriter) Flush() {
+	if len(p.buf) > 0 {
+		p.writeLine(append(p.buf, '\n'))
+		p.buf = nil
+	}
+}
+
+func (p *prefixWriter) writeLine(line []byte) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	io.WriteString(p.out, p.prefix)
+	p.out.Write(line)
+}
+
+// useColour reports whether ANSI colours should be written to w: only for
+// terminals, and never with NO_COLOR set or GITWO_COLOR=false
+func useColour(w io.Writer) bool {
+	if os.Getenv("NO_COLOR") != "" || strings.EqualFold(os.Getenv("GITWO_COLOR"), "false") {
+		return false
+	}
+	f, ok := w.(*os.File)
+	return ok && isTerminal(f)
+}
+
+var ansiPalette = []string{"36", "33", "35", "32", "34", "91", "96", "93"}
+
+func ansiColour(i int, s string) string {
+	return "\033[" + ansiPalette[i%len(ansiPalette)] + "m" + s + "\033[0m"
+}
+
+func ansiBold(colour bool, s string) string {
+	if !colour {
+		return s
+	}
+	return "\033[1m" + s + "\033[0m"
+}
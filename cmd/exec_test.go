@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"bytes"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecCommand(t *testing.T) {
+	repo := newTestRepo(t)
+	parent := filepath.Dir(repo)
+	for _, b := range []string{"api", "web"} {
+		out, err := exec.Command("git", "-C", repo, "worktree", "add", "-q", "-b", "feature/"+b, filepath.Join(parent, b)).CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+
+	run := func(t *testing.T, args ...string) (string, error) {
+		t.Helper()
+		var out bytes.Buffer
+		rootCmd.SetOut(&out)
+		t.Cleanup(func() {
+			rootCmd.SetOut(nil)
+			execFilters, execGroup, execFailFast, execTimeout, execJobs = nil, false, false, 0, 4
+		})
+		rootCmd.SetArgs(args)
+		err := rootCmd.Execute()
+		return out.String(), err
+	}
+
+	t.Run("runs in every worktree with GITWO_ env", func(t *testing.T) {
+		out, err := run(t, "exec", "--", "echo branch=$GITWO_BRANCH action=$GITWO_ACTION")
+		require.NoError(t, err)
+		assert.Contains(t, out, "shop │ branch=main action=exec")
+		assert.Contains(t, out, "api  │ branch=feature/api action=exec")
+		assert.Contains(t, out, "web  │ branch=feature/web action=exec")
+	})
+
+	t.Run("filters by glob and groups output", func(t *testing.T) {
+		out, err := run(t, "foreach", "--group", "--filter", "feature/*", "--filter", "linked", "--", "pwd")
+		require.NoError(t, err)
+		assert.Contains(t, out, "==> ../api (feature/api)\n"+filepath.Join(parent, "api")+"\n")
+		assert.NotContains(t, out, "(main)")
+	})
+
+	t.Run("reports failures and exits non-zero", func(t *testing.T) {
+		out, err := run(t, "exec", "--", `test "$GITWO_BRANCH" != feature/web || exit 3`)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed in 1 of 3")
+		assert.Regexp(t, `\.\./web\s+feature/web\s+3\s+failed`, out)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		out, err := run(t, "exec", "--filter", "api", "--timeout", "100ms", "--", "sleep", "5")
+		require.Error(t, err)
+		assert.Regexp(t, `feature/api\s+-\s+timeout`, out)
+	})
+}
+
+func TestPrefixWriter(t *testing.T) {
+	var out bytes.Buffer
+	w := &prefixWriter{mu: &sync.Mutex{}, out: &out, prefix: "[a] "}
+	w.Write([]byte("one\ntw"))
+	w.Write([]byte("o\nthree"))
+	w.Flush()
+	assert.Equal(t, "[a] one\n[a] two\n[a] three\n", out.String())
+}
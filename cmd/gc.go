@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gitwohq/gitwo/internal/wt"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	gcCmd := &cobra.Command{
+		Use:   "gc",
+		Short: "Remove expired temporary worktrees",
+		Long: `Remove temporary worktrees (gitwo review) whose --ttl has passed.
+
+Expired worktrees that have uncommitted changes, untracked files or commits
+that are on no branch are kept and reported, as is the one your shell is in.
+Every gitwo command does this cleanup in passing; gc does it explicitly and
+also lists the temporary worktrees that have not expired yet.
+
+Examples:
+  gitwo gc`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := cmd.OutOrStdout()
+			expired, err := wt.CleanExpired(time.Now())
+			if err != nil {
+				return err
+			}
+			reportExpired(out, expired)
+
+			items, err := wt.List()
+			if err != nil {
+				return err
+			}
+			active := 0
+			for _, it := range items {
+				if m, err := wt.ReadMeta(it.Path); err == nil && m.Ephemeral != nil && !m.Ephemeral.Expired(time.Now()) {
+					fmt.Fprintf(out, "Keeping %s (%s) until %s\n", displayPath(it.Path), m.Ephemeral.Ref, m.Ephemeral.Expires.Format(time.DateTime))
+					active++
+				}
+			}
+			if len(expired) == 0 && active == 0 {
+				fmt.Fprintln(out, "No temporary worktrees.")
+			}
+			return nil
+		},
+	}
+
+	rootCmd.AddCommand(gcCmd)
+}
+
+// reportExpired prints what CleanExpired did
+func reportExpired(out io.Writer, expired []wt.ExpiredWorktree) {
+	for _, e := range expired {
+		if e.Removed {
+			fmt.Fprintf(out, "Removed expired worktree %s (%s)\n", displayPath(e.Item.Path), e.Meta.Ephemeral.Ref)
+		} else {
+			fmt.Fprintf(out, "warning: expired worktree %s (%s) was kept: %s\n", displayPath(e.Item.Path), e.Meta.Ephemeral.Ref, e.Kept)
+		}
+	}
+}
+
+// cleanExpiredInPassing runs before every command: when the repository has
+// temporary worktrees, expired ones are cleaned up and reported on stderr.
+// It stays silent outside repositories and when there is nothing to do.
+func cleanExpiredInPassing(cmd *cobra.Command) {
+	if !cmd.HasParent() {
+		return
+	}
+	switch cmd.Name() {
+	case "gc", "help", "completion", "shell-init", "shell-install", cobra.ShellCompRequestCmd, cobra.ShellCompNoDescRequestCmd:
+		return
+	}
+	root, err := wt.MainRoot()
+	if err != nil {
+		return
+	}
+	entries, err := os.ReadDir(filepath.Join(root, wt.EphemeralDir))
+	if err != nil || len(entries) == 0 || (len(entries) == 1 && entries[0].Name() == ".gitignore") {
+		return
+	}
+	expired, err := wt.CleanExpired(time.Now())
+	if err != nil {
+		return
+	}
+	reportExpired(cmd.ErrOrStderr(), expired)
+}
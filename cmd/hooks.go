@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/gitwohq/gitwo/internal/config"
+	"github.com/gitwohq/gitwo/internal/hooks"
+)
+
+// noHooks is bound to the --no-hooks flag of every command that runs hooks
+var noHooks bool
+
+// hookRun describes one lifecycle event to run hooks for
+type hookRun struct {
+	Event        string // pre_add, post_add, pre_remove, post_remove
+	Action       string // gitwo action exposed as GITWO_ACTION
+	RepoPath     string // repository root holding .gitwo/
+	Branch       string
+	WorktreePath string
+	Dir          string // working directory for the hook commands
+}
+
+// runHooks executes the hooks configured for run.Event: first the inline
+// hooks from .gitwo/config.yml, then those from .gitwo/hooks/<event>.yml.
+// Hooks are skipped when disabled in config or with --no-hooks.
+func runHooks(out io.Writer, cfg *config.Config, run hookRun) error {
+	if noHooks || cfg == nil || !cfg.Hooks.Enabled {
+		return nil
+	}
+
+	var list []hooks.Hook
+	for _, h := range cfg.Hooks.ForEvent(run.Event) {
+		list = append(list, hooks.Hook{
+			Type:        h.Type,
+			Command:     h.Command,
+			Description: h.Description,
+			Language:    h.Language,
+		})
+	}
+	fileHooks, err := hooks.LoadHooks(run.RepoPath, run.Event)
+	if err != nil {
+		return fmt.Errorf("failed to load %s hooks: %w", run.Event, err)
+	}
+	list = append(list, fileHooks...)
+	if len(list) == 0 {
+		return nil
+	}
+
+	env := hooks.CreateActionEnvironment(run.Action, run.RepoPath, run.Branch, run.WorktreePath, cfg.HookVars())
+	env["GITWO_EVENT"] = run.Event
+
+	for _, h := range list {
+		desc := h.Description
+		if desc == "" {
+			desc = h.Command
+		}
+		fmt.Fprintf(out, "• %s hook: %s\n", run.Event, desc)
+		if err := hooks.ExecuteHooksInDir([]hooks.Hook{h}, env, run.Dir); err != nil {
+			return fmt.Errorf("%s hook %q failed: %w", run.Event, desc, err)
+		}
+	}
+	return nil
+}
+
+// loadRepoConfig loads .gitwo/config.yml for the repository at repoPath,
+// falling back to defaults when it is missing or unreadable.
+func loadRepoConfig(out io.Writer, repoPath string) *config.Config {
+	cfg, err := config.LoadConfig(repoPath)
+	if err != nil {
+		fmt.Fprintf(out, "warning: %v (using defaults)\n", err)
+	}
+	return cfg
+}
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gitwohq/gitwo/internal/config"
+	"github.com/gitwohq/gitwo/internal/hooks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunHooks(t *testing.T) {
+	repoPath := t.TempDir()
+	worktreePath := t.TempDir()
+
+	// Hook files as written by `gitwo init`
+	require.NoError(t, config.SaveHooks(repoPath, hooks.EventPostAdd, []config.Hook{
+		{Type: "command", Command: `echo "$GITWO_ACTION:$GITWO_EVENT" > post.txt`, Description: "Write marker"},
+	}))
+
+	cfg := config.DefaultConfig()
+	cfg.Hooks.PreAdd = []config.Hook{
+		{Type: "command", Command: "touch pre.txt", Description: "Inline pre hook"},
+	}
+
+	run := hookRun{
+		Event:        hooks.EventPreAdd,
+		Action:       "new",
+		RepoPath:     repoPath,
+		Branch:       "feature/x",
+		WorktreePath: worktreePath,
+		Dir:          repoPath,
+	}
+
+	t.Run("runs inline and file hooks in the requested directory", func(t *testing.T) {
+		var out bytes.Buffer
+		require.NoError(t, runHooks(&out, cfg, run))
+		assert.FileExists(t, filepath.Join(repoPath, "pre.txt"))
+		assert.Contains(t, out.String(), "pre_add hook: Inline pre hook")
+
+		post := run
+		post.Event = hooks.EventPostAdd
+		post.Dir = worktreePath
+		require.NoError(t, runHooks(&out, cfg, post))
+		data, err := os.ReadFile(filepath.Join(worktreePath, "post.txt"))
+		require.NoError(t, err)
+		assert.Equal(t, "new:post_add\n", string(data))
+	})
+
+	t.Run("skips hooks when disabled in config", func(t *testing.T) {
+		disabled := *cfg
+		disabled.Hooks.Enabled = false
+		dir := t.TempDir()
+		r := run
+		r.Dir = dir
+		require.NoError(t, runHooks(&bytes.Buffer{}, &disabled, r))
+		assert.NoFileExists(t, filepath.Join(dir, "pre.txt"))
+	})
+
+	t.Run("skips hooks with --no-hooks", func(t *testing.T) {
+		noHooks = true
+		defer func() { noHooks = false }()
+		dir := t.TempDir()
+		r := run
+		r.Dir = dir
+		require.NoError(t, runHooks(&bytes.Buffer{}, cfg, r))
+		assert.NoFileExists(t, filepath.Join(dir, "pre.txt"))
+	})
+
+	t.Run("reports failing hooks", func(t *testing.T) {
+		failing := *cfg
+		failing.Hooks.PreAdd = []config.Hook{{Type: "command", Command: "exit 3", Description: "Always fails"}}
+		err := runHooks(&bytes.Buffer{}, &failing, run)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "Always fails")
+	})
+}
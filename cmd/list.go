@@ -2,15 +2,20 @@ package cmd
 
 import (
 	"fmt"
-	"os"
-	"path/filepath"
 	"text/tabwriter"
 
 	"github.com/gitwohq/gitwo/internal/wt"
 	"github.com/spf13/cobra"
 )
 
-var listVerbose bool
+var (
+	listVerbose   bool
+	listJobs      int
+	listJSON      bool
+	listPorcelain string
+	listNul       bool
+	listFormat    string
+)
 
 func init() {
 	listCmd := &cobra.Command{
@@ -21,38 +26,130 @@ func init() {
 
 Examples:
   gitwo list                    # Basic list
-  gitwo list --verbose          # Detailed information`,
+  gitwo list --verbose          # Detailed information
+  gitwo list --json             # JSON for scripts and editor plugins
+  gitwo list --porcelain -z     # Stable, NUL-delimited fields (v1)
+  gitwo list --format '{{.Path}} {{.Branch}}'
+
+Detached worktrees show their tag, the ref they were created from or the
+short commit in parentheses instead of a branch. Locked worktrees show
+"locked (<reason>)" in a LOCK column.
+
+With --verbose, each worktree shows its local changes (staged, modified,
+untracked, conflicted), any operation in progress (rebase, merge,
+cherry-pick, revert, bisect), whether it is prunable, and how far it is
+ahead/behind its upstream (UPSTREAM) and main_branch (MAIN). Sparse worktrees
+list their sparse-checkout directories in a SPARSE column, and submodules
+checked out at a commit other than the recorded one show up in a SUBMODULES
+column.
+
+--json prints {"version": 1, "worktrees": [...]} where each worktree has
+path, branch, ref (detached worktrees only), head, detached, bare, locked,
+prunable, is_current and is_main (plus a status object with --verbose).
+--format templates use the same fields in Go form: {{.Path}}, {{.Branch}},
+{{.IsCurrent}}, ...
+
+--porcelain=v1 prints one worktree per line with tab-separated fields in this
+order: path, branch, head, detached, bare, locked, prunable, current, main
+(booleans as 0/1). With -z every field is NUL-terminated instead.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			machine := 0
+			for _, set := range []bool{listJSON, listPorcelain != "", listFormat != ""} {
+				if set {
+					machine++
+				}
+			}
+			if machine > 1 {
+				return fmt.Errorf("--json, --porcelain and --format are mutually exclusive")
+			}
+			if listNul && listPorcelain == "" {
+				return fmt.Errorf("-z requires --porcelain")
+			}
+
 			items, err := wt.List()
 			if err != nil {
 				return err
 			}
+			current := wt.CurrentIndex(items)
+
+			var statuses []wt.Status
+			if listVerbose {
+				mainBranch := ""
+				if root, err := wt.MainRoot(); err == nil {
+					mainBranch = loadRepoConfig(cmd.ErrOrStderr(), root).MainBranch
+				}
+				statuses = wt.CollectStatus(items, mainBranch, listJobs)
+			}
+
+			out := cmd.OutOrStdout()
+			switch {
+			case listJSON:
+				return writeListJSON(out, newListEntries(items, statuses, current))
+			case listPorcelain != "":
+				return writeListPorcelain(out, listPorcelain, newListEntries(items, statuses, current), listNul)
+			case listFormat != "":
+				return writeListTemplate(out, listFormat, newListEntries(items, statuses, current))
+			}
+
 			if len(items) == 0 {
-				fmt.Println("No worktrees found.")
+				fmt.Fprintln(out, "No worktrees found.")
 				return nil
 			}
 
-			// Get current working directory
-			currentDir, err := os.Getwd()
-			if err != nil {
-				currentDir = ""
+			// Lock state gets its own column when any worktree is locked
+			anyLocked := false
+			for _, it := range items {
+				anyLocked = anyLocked || it.Locked
+			}
+			lockCol := func(it wt.WorktreeItem) string {
+				if !anyLocked {
+					return ""
+				}
+				if it.Locked {
+					return "\t" + it.LockSummary()
+				}
+				return "\t-"
+			}
+			lockHeader := ""
+			if anyLocked {
+				lockHeader = "\tLOCK"
 			}
-			currentDir, _ = filepath.Abs(currentDir)
 
-			tw := tabwriter.NewWriter(os.Stdout, 2, 4, 2, ' ', 0)
+			tw := tabwriter.NewWriter(out, 2, 4, 2, ' ', 0)
 
 			if listVerbose {
-				fmt.Fprintln(tw, "PATH\tBRANCH\tHEAD\tSTATUS")
-				for _, it := range items {
-					status := getWorktreeStatus(it.Path)
-					path := formatPath(it.Path, currentDir)
-					fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", path, it.Branch, it.Head, status)
+				// Sparse checkouts and drifted submodules get their own
+				// columns when any worktree has them
+				anySparse, anyDrift := false, false
+				for _, st := range statuses {
+					anySparse = anySparse || st.Sparse != nil
+					anyDrift = anyDrift || len(st.DriftedSubmodules) > 0
+				}
+				extraHeader := ""
+				if anySparse {
+					extraHeader += "\tSPARSE"
+				}
+				if anyDrift {
+					extraHeader += "\tSUBMODULES"
+				}
+				fmt.Fprintln(tw, "PATH\tBRANCH\tHEAD\tSTATUS\tUPSTREAM\tMAIN"+extraHeader+lockHeader)
+				for i, it := range items {
+					st := statuses[i]
+					path := formatPath(it.Path, i == current)
+					extraCols := ""
+					if anySparse {
+						extraCols += "\t" + st.SparseSummary()
+					}
+					if anyDrift {
+						extraCols += "\t" + st.SubmoduleSummary()
+					}
+					fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s%s%s\n", path, it.BranchColumn(), it.Head, st, st.UpstreamSummary(), st.MainSummary(), extraCols, lockCol(it))
 				}
 			} else {
-				fmt.Fprintln(tw, "PATH\tBRANCH\tHEAD")
-				for _, it := range items {
-					path := formatPath(it.Path, currentDir)
-					fmt.Fprintf(tw, "%s\t%s\t%s\n", path, it.Branch, it.Head)
+				fmt.Fprintln(tw, "PATH\tBRANCH\tHEAD"+lockHeader)
+				for i, it := range items {
+					path := formatPath(it.Path, i == current)
+					fmt.Fprintf(tw, "%s\t%s\t%s%s\n", path, it.BranchColumn(), it.Head, lockCol(it))
 				}
 			}
 
@@ -61,20 +158,18 @@ Examples:
 	}
 
 	listCmd.Flags().BoolVarP(&listVerbose, "verbose", "v", false, "Show detailed information")
+	listCmd.Flags().IntVarP(&listJobs, "jobs", "j", 8, "Number of worktrees to inspect in parallel with --verbose")
+	listCmd.Flags().BoolVar(&listJSON, "json", false, "Print worktrees as JSON (add --verbose for status)")
+	listCmd.Flags().StringVar(&listPorcelain, "porcelain", "", "Print a stable, script-friendly format (v1)")
+	listCmd.Flags().Lookup("porcelain").NoOptDefVal = "v1"
+	listCmd.Flags().BoolVarP(&listNul, "null", "z", false, "With --porcelain, terminate fields with NUL instead of tabs/newlines")
+	listCmd.Flags().StringVar(&listFormat, "format", "", "Print each worktree with a Go template, e.g. '{{.Path}} {{.Branch}}'")
 
 	rootCmd.AddCommand(listCmd)
 }
 
-func getWorktreeStatus(worktreePath string) string {
-	// Simple status check - check if there are uncommitted changes
-	// For now, return a basic status
-	// TODO: Implement actual status checking
-	return "CLEAN"
-}
-
-func formatPath(path, currentDir string) string {
-	absPath, _ := filepath.Abs(path)
-	if absPath == currentDir {
+func formatPath(path string, current bool) string {
+	if current {
 		return fmt.Sprintf("→ %s", path)
 	}
 	return path
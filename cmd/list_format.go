@@ -0,0 +1,165 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+
+	"github.com/gitwohq/gitwo/internal/wt"
+)
+
+// listSchemaVersion is bumped whenever fields are removed or change meaning
+// in the --json output. New fields may be added without a bump.
+const listSchemaVersion = 1
+
+// listEntry is the stable --json/--format schema for `gitwo list`
+type listEntry struct {
+	Path           string      `json:"path"`
+	Branch         string      `json:"branch"`
+	Ref            string      `json:"ref,omitempty"`
+	Head           string      `json:"head"`
+	Detached       bool        `json:"detached"`
+	Bare           bool        `json:"bare"`
+	Locked         bool        `json:"locked"`
+	LockReason     string      `json:"lock_reason,omitempty"`
+	Prunable       bool        `json:"prunable"`
+	PrunableReason string      `json:"prunable_reason,omitempty"`
+	IsCurrent      bool        `json:"is_current"`
+	IsMain         bool        `json:"is_main"`
+	Status         *listStatus `json:"status,omitempty"`
+}
+
+// listStatus is the status object added to each entry with --verbose
+type listStatus struct {
+	Clean      bool     `json:"clean"`
+	Staged     int      `json:"staged"`
+	Unstaged   int      `json:"unstaged"`
+	Untracked  int      `json:"untracked"`
+	Conflicted int      `json:"conflicted"`
+	Upstream   string   `json:"upstream,omitempty"`
+	Ahead      int      `json:"ahead"`
+	Behind     int      `json:"behind"`
+	Main       string   `json:"main,omitempty"`
+	MainAhead  int      `json:"main_ahead"`
+	MainBehind int      `json:"main_behind"`
+	Operation  string   `json:"operation,omitempty"`
+	Sparse     []string `json:"sparse,omitempty"`
+	Submodules []string `json:"drifted_submodules,omitempty"`
+	Error      string   `json:"error,omitempty"`
+}
+
+// newListEntries converts worktree items (and optional statuses) to the
+// output schema. The first item reported by git is the main worktree.
+func newListEntries(items []wt.WorktreeItem, statuses []wt.Status, current int) []listEntry {
+	entries := make([]listEntry, len(items))
+	for i, it := range items {
+		entries[i] = listEntry{
+			Path:           it.Path,
+			Branch:         it.Branch,
+			Ref:            it.Ref,
+			Head:           it.Head,
+			Detached:       it.Detached,
+			Bare:           it.Bare,
+			Locked:         it.Locked,
+			LockReason:     it.LockReason,
+			Prunable:       it.Prunable,
+			PrunableReason: it.PrunableReason,
+			IsCurrent:      i == current,
+			IsMain:         i == 0,
+		}
+		if statuses != nil {
+			st := statuses[i]
+			entries[i].Status = &listStatus{
+				Clean:      st.Err == nil && !st.Dirty(),
+				Staged:     st.Staged,
+				Unstaged:   st.Unstaged,
+				Untracked:  st.Untracked,
+				Conflicted: st.Conflicted,
+				Upstream:   st.Upstream,
+				Ahead:      st.Ahead,
+				Behind:     st.Behind,
+				Main:       st.Main,
+				MainAhead:  st.MainAhead,
+				MainBehind: st.MainBehind,
+				Operation:  st.Operation,
+				Sparse:     st.Sparse,
+				Submodules: st.DriftedSubmodules,
+			}
+			if st.Err != nil {
+				entries[i].Status.Error = st.Err.Error()
+			}
+		}
+	}
+	return entries
+}
+
+// writeListJSON prints {"version": 1, "worktrees": [...]}
+func writeListJSON(w io.Writer, entries []listEntry) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(struct {
+		Version   int         `json:"version"`
+		Worktrees []listEntry `json:"worktrees"`
+	}{listSchemaVersion, entries})
+}
+
+// writeListPorcelain prints one record per worktree with a fixed field order.
+//
+// v1 fields: path, branch, head, detached, bare, locked, prunable, current, main
+// (booleans as 0/1). Fields are tab-separated and records newline-terminated;
+// with nul every field is NUL-terminated instead, so each record is exactly
+// nine fields.
+func writeListPorcelain(w io.Writer, version string, entries []listEntry, nul bool) error {
+	if version != "v1" {
+		return fmt.Errorf("unsupported porcelain version %q (supported: v1)", version)
+	}
+	for _, e := range entries {
+		fields := []string{
+			e.Path, e.Branch, e.Head,
+			boolField(e.Detached), boolField(e.Bare), boolField(e.Locked),
+			boolField(e.Prunable), boolField(e.IsCurrent), boolField(e.IsMain),
+		}
+		var err error
+		if nul {
+			_, err = io.WriteString(w, strings.Join(fields, "\x00")+"\x00")
+		} else {
+			_, err = fmt.Fprintln(w, strings.Join(fields, "\t"))
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func boolField(b bool) string {
+	if b {
+		return "1"
+	}
+	return "0"
+}
+
+// writeListTemplate executes a text/template once per entry, e.g.
+// '{{.Path}} {{.Branch}}'. A trailing newline is added when missing.
+func writeListTemplate(w io.Writer, format string, entries []listEntry) error {
+	tmpl, err := template.New("format").Parse(format)
+	if err != nil {
+		return fmt.Errorf("invalid --format template: %w", err)
+	}
+	for _, e := range entries {
+		var sb strings.Builder
+		if err := tmpl.Execute(&sb, e); err != nil {
+			return fmt.Errorf("invalid --format template: %w", err)
+		}
+		line := sb.String()
+		if !strings.HasSuffix(line, "\n") {
+			line += "\n"
+		}
+		if _, err := io.WriteString(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
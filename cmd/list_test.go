@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/gitwohq/gitwo/internal/wt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testListEntries() []listEntry {
+	items := []wt.WorktreeItem{
+		{Path: "/src/shop", Head: "aaa", Branch: "main"},
+		{Path: "/src/shop-feature-a", Head: "bbb", Branch: "feature/a", Locked: true, LockReason: "usb"},
+		{Path: "/src/shop-review", Head: "ccc", Detached: true},
+	}
+	statuses := []wt.Status{{}, {Unstaged: 2, Upstream: "origin/feature/a", Ahead: 1}, {}}
+	return newListEntries(items, statuses, 1)
+}
+
+func TestWriteListJSON(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, writeListJSON(&buf, testListEntries()))
+
+	var doc struct {
+		Version   int                      `json:"version"`
+		Worktrees []map[string]interface{} `json:"worktrees"`
+	}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &doc))
+	assert.Equal(t, 1, doc.Version)
+	require.Len(t, doc.Worktrees, 3)
+
+	main, feature, review := doc.Worktrees[0], doc.Worktrees[1], doc.Worktrees[2]
+	assert.Equal(t, true, main["is_main"])
+	assert.Equal(t, false, main["is_current"])
+	assert.Equal(t, true, feature["is_current"])
+	assert.Equal(t, true, feature["locked"])
+	assert.Equal(t, "usb", feature["lock_reason"])
+	assert.Equal(t, true, review["detached"])
+
+	status := feature["status"].(map[string]interface{})
+	assert.Equal(t, false, status["clean"])
+	assert.Equal(t, float64(2), status["unstaged"])
+	assert.Equal(t, "origin/feature/a", status["upstream"])
+}
+
+func TestWriteListPorcelain(t *testing.T) {
+	entries := testListEntries()
+
+	t.Run("line delimited", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, writeListPorcelain(&buf, "v1", entries, false))
+		lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+		require.Len(t, lines, 3)
+		assert.Equal(t, "/src/shop\tmain\taaa\t0\t0\t0\t0\t0\t1", lines[0])
+		assert.Equal(t, "/src/shop-feature-a\tfeature/a\tbbb\t0\t0\t1\t0\t1\t0", lines[1])
+		assert.Equal(t, "/src/shop-review\t\tccc\t1\t0\t0\t0\t0\t0", lines[2])
+	})
+
+	t.Run("NUL delimited", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, writeListPorcelain(&buf, "v1", entries, true))
+		fields := strings.Split(strings.TrimSuffix(buf.String(), "\x00"), "\x00")
+		assert.Len(t, fields, 27)
+		assert.Equal(t, "/src/shop-feature-a", fields[9])
+	})
+
+	t.Run("unknown version", func(t *testing.T) {
+		assert.Error(t, writeListPorcelain(&bytes.Buffer{}, "v9", entries, false))
+	})
+}
+
+func TestWriteListTemplate(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, writeListTemplate(&buf, "{{.Path}} {{.Branch}}{{if .IsCurrent}} *{{end}}", testListEntries()))
+	assert.Equal(t, "/src/shop main\n/src/shop-feature-a feature/a *\n/src/shop-review \n", buf.String())
+
+	assert.Error(t, writeListTemplate(&bytes.Buffer{}, "{{.Nope", testListEntries()))
+	assert.Error(t, writeListTemplate(&bytes.Buffer{}, "{{.Nope}}", testListEntries()))
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/gitwohq/gitwo/internal/wt"
+	"github.com/spf13/cobra"
+)
+
+var lockReason string
+
+func init() {
+	lockCmd := &cobra.Command{
+		Use:   "lock <worktree>",
+		Short: "Lock a worktree so it is not pruned, moved or removed",
+		Long: `Lock a worktree with 'git worktree lock'.
+
+Locked worktrees are kept by 'git worktree prune', and gitwo's remove and
+prune refuse them unless forced. Use it for worktrees on removable or slow
+storage, or to mark long-lived release worktrees. The reason is shown by
+'gitwo list'.
+
+Examples:
+  gitwo lock release-2.4 --reason "long-lived release branch"
+  gitwo lock ../usb-checkout --reason "on USB drive"`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			item, err := wt.Resolve(args[0])
+			if err != nil {
+				return err
+			}
+			if err := wt.Lock(item, lockReason); err != nil {
+				return err
+			}
+			item.Locked, item.LockReason = true, lockReason
+			fmt.Fprintf(cmd.OutOrStdout(), "🔒 %s %s\n", displayPath(item.Path), item.LockSummary())
+			return nil
+		},
+	}
+	lockCmd.Flags().StringVar(&lockReason, "reason", "", "why the worktree is locked")
+
+	unlockCmd := &cobra.Command{
+		Use:   "unlock <worktree>",
+		Short: "Unlock a locked worktree",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			item, err := wt.Resolve(args[0])
+			if err != nil {
+				return err
+			}
+			if err := wt.Unlock(item); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "🔓 %s unlocked\n", displayPath(item.Path))
+			return nil
+		},
+	}
+
+	rootCmd.AddCommand(lockCmd, unlockCmd)
+}
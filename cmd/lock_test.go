@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"bytes"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLockCommand_ShownInList(t *testing.T) {
+	repo := newTestRepo(t)
+	path := filepath.Join(filepath.Dir(repo), "release")
+	out, err := exec.Command("git", "-C", repo, "worktree", "add", "-q", "-b", "release/2.4", path).CombinedOutput()
+	require.NoError(t, err, string(out))
+
+	var buf bytes.Buffer
+	rootCmd.SetOut(&buf)
+	t.Cleanup(func() {
+		rootCmd.SetOut(nil)
+		lockReason = ""
+	})
+
+	rootCmd.SetArgs([]string{"lock", "release", "--reason", "on usb disk"})
+	require.NoError(t, rootCmd.Execute())
+
+	buf.Reset()
+	rootCmd.SetArgs([]string{"list"})
+	require.NoError(t, rootCmd.Execute())
+	assert.Contains(t, buf.String(), "LOCK")
+	assert.Contains(t, buf.String(), "locked (on usb disk)")
+
+	rootCmd.SetArgs([]string{"unlock", "release"})
+	require.NoError(t, rootCmd.Execute())
+
+	buf.Reset()
+	rootCmd.SetArgs([]string{"list"})
+	require.NoError(t, rootCmd.Execute())
+	assert.NotContains(t, buf.String(), "LOCK")
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/gitwohq/gitwo/internal/pathtmpl"
+	"github.com/gitwohq/gitwo/internal/wt"
+	"github.com/spf13/cobra"
+)
+
+var moveForce bool
+
+func init() {
+	moveCmd := &cobra.Command{
+		Use:     "move <worktree> <new-path>",
+		Aliases: []string{"mv"},
+		Short:   "Move a worktree to another directory",
+		Long: `Move a worktree with 'git worktree move' and keep everything consistent.
+
+When <new-path> is an existing directory the worktree is moved inside it.
+Afterwards the .git links are verified (running 'git worktree repair' if they
+are broken) and gitwo's metadata for the worktree is updated. If your shell is
+inside the worktree, the shell wrapper follows it to the new location.
+
+The main worktree cannot be moved, and neither can worktrees with initialized
+submodules (git refuses; gitwo explains the workaround). Locked worktrees
+need --force.
+
+Examples:
+  gitwo move login ../archive/login
+  gitwo mv feature/payments ~/src/trees/`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := humanOut(cmd)
+
+			item, err := wt.Resolve(args[0])
+			if err != nil {
+				return err
+			}
+			repoPath, err := wt.MainRoot()
+			if err != nil {
+				return err
+			}
+
+			// Resolve the destination before leaving the current directory
+			dest, err := filepath.Abs(pathtmpl.ExpandHome(args[1]))
+			if err != nil {
+				return err
+			}
+
+			rel, err := leaveWorktree(item.Path, repoPath)
+			if err != nil {
+				return err
+			}
+
+			newPath, err := wt.Move(item, dest, wt.MoveOptions{Force: moveForce})
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(out, "Moved %s (%s) to %s\n", displayPath(item.Path), item.Label(), displayPath(newPath))
+
+			followWorktree(cmd, out, newPath, rel)
+			return nil
+		},
+	}
+
+	moveCmd.Flags().BoolVarP(&moveForce, "force", "f", false, "move locked worktrees too")
+	moveCmd.Flags().BoolVar(&shellMode, "shell", false, "print a cd command for the shell wrapper")
+	rootCmd.AddCommand(moveCmd)
+}
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMoveCommand_ShellFollowsFromInside(t *testing.T) {
+	repo := newTestRepo(t)
+	parent := filepath.Dir(repo)
+	path := filepath.Join(parent, "login")
+	out, err := exec.Command("git", "-C", repo, "worktree", "add", "-q", "-b", "feature/login", path).CombinedOutput()
+	require.NoError(t, err, string(out))
+	require.NoError(t, os.MkdirAll(filepath.Join(path, "src"), 0o755))
+	t.Chdir(filepath.Join(path, "src"))
+
+	var stdout, stderr bytes.Buffer
+	rootCmd.SetOut(&stdout)
+	rootCmd.SetErr(&stderr)
+	t.Cleanup(func() {
+		rootCmd.SetOut(nil)
+		rootCmd.SetErr(nil)
+		shellMode = false
+		moveForce = false
+	})
+
+	dest := filepath.Join(parent, "archive", "login")
+	rootCmd.SetArgs([]string{"mv", "login", dest, "--shell"})
+	require.NoError(t, rootCmd.Execute())
+
+	assert.NoDirExists(t, path)
+	assert.DirExists(t, filepath.Join(dest, "src"))
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	assert.Equal(t, "cd "+filepath.Join(dest, "src"), lines[len(lines)-1])
+	assert.Contains(t, stderr.String(), "Moved")
+
+	list, err := exec.Command("git", "-C", repo, "worktree", "list").CombinedOutput()
+	require.NoError(t, err)
+	assert.Contains(t, string(list), dest)
+}
@@ -2,23 +2,33 @@ package cmd
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
+	"github.com/gitwohq/gitwo/internal/config"
 	"github.com/gitwohq/gitwo/internal/gitutil"
+	"github.com/gitwohq/gitwo/internal/hooks"
+	"github.com/gitwohq/gitwo/internal/naming"
+	"github.com/gitwohq/gitwo/internal/pathtmpl"
+	"github.com/gitwohq/gitwo/internal/wt"
 	"github.com/spf13/cobra"
 )
 
 var (
 	newStartRef       string
 	newAutoSwitch     bool
-	newOutputShell    bool
 	newCreateScript   bool
 	newSourceFunction bool
 	newAutoSource     bool
 	newPrefix         string
 	newWorktreesDir   string
+	newSparse         []string
+	newCarry          bool
+	newUntracked      bool
+	newFromStash      string
 )
 
 // newCmd represents the new command
@@ -26,12 +36,37 @@ var newCmd = &cobra.Command{
 	Use:   "new <name>",
 	Short: "Create a new branch (by default 'feature/<name>') and attach a worktree",
 	Long: strings.TrimSpace(`
-Create a new branch (default prefix 'feature/') from --start-point (default HEAD) and attach a worktree.
+Create a new branch (default prefix 'feature/') from --start-point (default: main_branch,
+or HEAD when it does not exist) and attach a worktree.
+
+The name is checked against git's branch name rules and the naming: policy in
+.gitwo/config.yml, which can lowercase and slugify it, require a ticket id, limit
+its length and restrict prefixes. gitwo prints the name when it changes it.
+
+The worktree path is built from worktrees_dir and name_template in .gitwo/config.yml.
+Templates may use ${REPO}, ${BRANCH}, ${NAME}, ${PREFIX} and ${USER}; slashes in
+branch names become dashes. Command-line flags win over config values.
+
+With --sparse only the given directories (or sparse_profiles from config) are
+checked out, using cone-mode sparse-checkout for the new worktree only.
+
+Submodules are initialized and checked out in the new worktree. Objects the
+main worktree already has for a submodule are reused via --reference rather
+than cloned again.
+
+--carry moves the current worktree's uncommitted changes (staged and unstaged,
+plus untracked files with --include-untracked) into the new worktree and
+leaves the current one clean. --from-stash applies a stash entry instead. The
+changes travel as a stash, which is dropped once they applied cleanly; on
+conflicts it is kept so nothing is lost.
 
 Examples:
   gitwo new auth-refactor
   gitwo new hotfix-123 --prefix ''
   gitwo new rfc-xx --start-point origin/main
+  gitwo new api-fix --sparse services/api,libs/common
+  gitwo new login-fix --carry --include-untracked
+  gitwo new spike --from-stash stash@{1}
 `),
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
@@ -43,40 +78,81 @@ Examples:
 			return fmt.Errorf("name is required, e.g. 'gitwo new checkout-timeouts'")
 		}
 
+		if newUntracked && !newCarry {
+			return fmt.Errorf("--include-untracked requires --carry")
+		}
+		if newCarry && newFromStash != "" {
+			return fmt.Errorf("--carry and --from-stash are mutually exclusive")
+		}
+
 		// Guard: repo must have at least one commit
 		if !gitutil.HasHead() {
 			return fmt.Errorf("this repository has no commits yet.\nMake an initial commit before using 'gitwo new'")
 		}
 
-		// Compute branch and path
-		branch := name
-		if newPrefix != "" {
-			branch = fmt.Sprintf("%s%s", newPrefix, name)
+		var repoPath string
+		repoPath, err = wt.MainRoot()
+		if err != nil {
+			return err
+		}
+		cfg := loadRepoConfig(cmd.ErrOrStderr(), repoPath)
+		out := humanOut(cmd)
+
+		var nameRes naming.Result
+		nameRes, err = branchName(cmd, out, cfg, name, newPrefix)
+		if err != nil {
+			return err
+		}
+		branch := nameRes.Branch
+
+		// Start point: --start-point wins over main_branch
+		startPoint := newStartRef
+		if startPoint == "" {
+			startPoint = defaultStartPoint(cmd.ErrOrStderr(), cfg)
+		}
+
+		// Compute path from worktrees_dir and name_template
+		var path string
+		path, err = worktreeTarget(cfg, repoPath, newWorktreesDir, pathtmpl.Vars{Branch: branch, Name: nameRes.Name, Prefix: nameRes.Prefix})
+		if err != nil {
+			return err
+		}
+		err = os.MkdirAll(filepath.Dir(path), 0o755)
+		if err != nil {
+			return fmt.Errorf("failed to create worktrees dir %q: %w", filepath.Dir(path), err)
 		}
 
-		worktreesDir := newWorktreesDir
-		if worktreesDir == "" {
-			// default to ".gitwo" in repo root unless already configured elsewhere
-			worktreesDir = ".gitwo"
+		run := hookRun{
+			Event:        hooks.EventPreAdd,
+			Action:       "new",
+			RepoPath:     repoPath,
+			Branch:       branch,
+			WorktreePath: path,
+			Dir:          repoPath,
 		}
-		err = os.MkdirAll(worktreesDir, 0o755)
+		err = runHooks(out, cfg, run)
 		if err != nil {
-			return fmt.Errorf("failed to create worktrees dir %q: %w", worktreesDir, err)
+			return err
 		}
 
-		path := filepath.Join(worktreesDir, name)
+		// Changes to bring along travel as a stash
+		var carry *carriedChanges
+		carry, err = prepareCarry(out, branch)
+		if err != nil {
+			return err
+		}
 
-		// Build git worktree add args
-		var wtArgs []string
-		if newStartRef == "" {
-			wtArgs = []string{"-b", branch, path, "HEAD"}
-		} else {
-			wtArgs = []string{"-b", branch, path, newStartRef}
+		// Build git worktree add args; sparse worktrees are checked out later
+		sparse := expandSparse(cfg, newSparse)
+		wtArgs := []string{"-b", branch, path, startPoint}
+		if len(sparse) > 0 {
+			wtArgs = append([]string{"--no-checkout"}, wtArgs...)
 		}
 
 		// Run git worktree add
 		err = gitutil.GitWorktreeAdd(wtArgs...)
 		if err != nil {
+			carry.restore(out)
 			lower := strings.ToLower(err.Error())
 			if strings.Contains(lower, "invalid reference: head") {
 				return fmt.Errorf("cannot create a branch from HEAD: repository appears to have no commits.\nMake an initial commit, or specify --start-point <ref>")
@@ -84,12 +160,48 @@ Examples:
 			return err
 		}
 
+		if len(sparse) > 0 {
+			err = wt.SparseCheckout(path, sparse)
+			if err != nil {
+				carry.restore(out)
+				return fmt.Errorf("worktree created at %s without a checkout: %w\nretry with: gitwo sparse %s set %s", displayPath(path), err, branch, strings.Join(sparse, ","))
+			}
+		}
+
+		// Best effort: metadata only adds information
+		_ = wt.WriteMeta(path, wt.Meta{Created: time.Now()})
+
 		// Print guidance
-		fmt.Fprintf(cmd.OutOrStdout(), "Preparing worktree (new branch %q) at %s\n", branch, path)
+		fmt.Fprintf(out, "Preparing worktree (new branch %q from %s) at %s\n", branch, startPoint, displayPath(path))
+		if len(sparse) > 0 {
+			fmt.Fprintf(out, "Sparse checkout: %s\n", strings.Join(sparse, ", "))
+		}
+
+		carry.apply(out, path)
+
+		// worktree add leaves submodules empty; a failure here is reported
+		// but the worktree stays usable
+		if wt.HasSubmodules(path) {
+			var updated []wt.SubmoduleUpdate
+			updated, err = wt.UpdateSubmodules(path, cmd.ErrOrStderr())
+			if err != nil {
+				fmt.Fprintf(out, "warning: %v\n  retry with: git -C %s submodule update --init --recursive\n", err, displayPath(path))
+			} else {
+				fmt.Fprintf(out, "Submodules: %s\n", wt.SubmoduleUpdateSummary(updated))
+			}
+		}
+
+		run.Event = hooks.EventPostAdd
+		run.Dir = path
+		err = runHooks(out, cfg, run)
+		if err != nil {
+			return fmt.Errorf("worktree created at %s, but %w", displayPath(path), err)
+		}
+
+		emitCD(cmd, path)
 
 		// TODO: hook up shell helpers if needed
 		_ = newAutoSwitch
-		_ = newOutputShell
 		_ = newCreateScript
 		_ = newSourceFunction
 		_ = newAutoSource
@@ -101,15 +213,50 @@ Examples:
 func init() {
 	rootCmd.AddCommand(newCmd)
 
-	newCmd.Flags().StringVar(&newStartRef, "start-point", "HEAD", "start point ref (default HEAD)")
-	newCmd.Flags().StringVar(&newPrefix, "prefix", "feature/", "branch prefix to use (empty to disable)")
-	newCmd.Flags().StringVar(&newWorktreesDir, "worktrees-dir", "", "directory to place worktrees (default ./.gitwo)")
+	newCmd.Flags().StringVar(&newStartRef, "start-point", "", "start point ref (default: main_branch from config, or HEAD)")
+	newCmd.Flags().StringVar(&newPrefix, "prefix", "", "branch prefix to use, empty to disable (default: default_branch_prefix from config)")
+	newCmd.Flags().StringVar(&newWorktreesDir, "worktrees-dir", "", "directory to place worktrees (default: worktrees_dir from config)")
+	newCmd.Flags().BoolVar(&noHooks, "no-hooks", false, "skip pre_add/post_add hooks")
+	newCmd.Flags().StringSliceVar(&newSparse, "sparse", nil, "check out only these directories or sparse_profiles (comma-separated)")
+	newCmd.Flags().BoolVar(&newCarry, "carry", false, "move the current worktree's uncommitted changes into the new worktree")
+	newCmd.Flags().BoolVarP(&newUntracked, "include-untracked", "u", false, "with --carry, move untracked files too")
+	newCmd.Flags().StringVar(&newFromStash, "from-stash", "", "apply this stash entry (e.g. stash@{1}) in the new worktree")
 
 	// keep existing flags (if used by your shell helpers)
 	newCmd.Flags().BoolVar(&newAutoSwitch, "switch", false, "automatically switch to the new worktree after creation")
-	newCmd.Flags().BoolVar(&newOutputShell, "shell", false, "output shell command for switching to worktree")
+	newCmd.Flags().BoolVar(&shellMode, "shell", false, "output shell command for switching to worktree")
 	newCmd.Flags().BoolVar(&newCreateScript, "script", false, "create a shell script for easy switching")
 	newCmd.Flags().BoolVar(&newSourceFunction, "source", false, "output shell function for sourcing")
 	newCmd.Flags().BoolVar(&newAutoSource, "auto-source", false, "provide auto-source instructions")
 }
 
+// branchName turns a name from the command line into a branch: the prefix
+// (a --prefix flag wins over default_branch_prefix) is added and the naming
+// policy from config applied. Normalizations are reported on out.
+func branchName(cmd *cobra.Command, out io.Writer, cfg *config.Config, name, flagPrefix string) (naming.Result, error) {
+	prefix := cfg.DefaultBranchPrefix
+	if cmd.Flags().Changed("prefix") {
+		prefix = flagPrefix
+	}
+	res, err := cfg.Naming.Apply(prefix, name)
+	if err != nil {
+		return res, err
+	}
+	if note := res.Note(); note != "" {
+		fmt.Fprintf(out, "note: %s\n", note)
+	}
+	return res, nil
+}
+
+// defaultStartPoint returns the configured main_branch, or HEAD when that ref
+// does not exist (e.g. a repository without the remote).
+func defaultStartPoint(out io.Writer, cfg *config.Config) string {
+	if cfg.MainBranch == "" {
+		return "HEAD"
+	}
+	if !gitutil.BranchExists(cfg.MainBranch) {
+		fmt.Fprintf(out, "note: main_branch %q not found, starting from HEAD\n", cfg.MainBranch)
+		return "HEAD"
+	}
+	return cfg.MainBranch
+}
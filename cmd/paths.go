@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/gitwohq/gitwo/internal/config"
+	"github.com/gitwohq/gitwo/internal/pathtmpl"
+	"github.com/gitwohq/gitwo/internal/wt"
+)
+
+// worktreeTarget computes the path for a new worktree from worktrees_dir and
+// name_template. A --worktrees-dir flag (relative to the current directory)
+// wins over the configured directory (relative to the main worktree). When the
+// path is already taken a numeric suffix is added.
+func worktreeTarget(cfg *config.Config, mainRoot, worktreesDirFlag string, vars pathtmpl.Vars) (string, error) {
+	path, err := templatePath(cfg, mainRoot, worktreesDirFlag, vars)
+	if err != nil {
+		return "", err
+	}
+	return pathtmpl.Unique(path, worktreePathTaken), nil
+}
+
+// templatePath is the path worktrees_dir and name_template give for vars,
+// whether or not it is taken
+func templatePath(cfg *config.Config, mainRoot, worktreesDirFlag string, vars pathtmpl.Vars) (string, error) {
+	base, dir := mainRoot, cfg.WorktreesDir
+	if worktreesDirFlag != "" {
+		base, _ = os.Getwd()
+		dir = worktreesDirFlag
+	}
+	if vars.Repo == "" {
+		vars.Repo = filepath.Base(mainRoot)
+	}
+	if vars.User == "" {
+		vars.User = pathtmpl.CurrentUser()
+	}
+
+	return pathtmpl.WorktreePath(base, dir, cfg.NameTemplate, vars)
+}
+
+// worktreePathTaken reports whether path is registered as a worktree or is an
+// existing, non-empty directory or file.
+func worktreePathTaken(path string) bool {
+	if item, err := wt.FindByPath(path); err == nil && item != nil {
+		return true
+	}
+	fi, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+	if !fi.IsDir() {
+		return true
+	}
+	entries, _ := os.ReadDir(path)
+	return len(entries) > 0
+}
+
+// displayPath shows path relative to the current directory when that is shorter
+func displayPath(path string) string {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return path
+	}
+	if rel, err := filepath.Rel(cwd, path); err == nil && len(rel) < len(path) {
+		return rel
+	}
+	return path
+}
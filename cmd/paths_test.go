@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/gitwohq/gitwo/internal/config"
+	"github.com/gitwohq/gitwo/internal/naming"
+	"github.com/gitwohq/gitwo/internal/pathtmpl"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestRepo creates a repository with one commit and makes it the current directory
+func newTestRepo(t *testing.T) string {
+	t.Helper()
+	t.Setenv("GIT_AUTHOR_NAME", "Test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "Test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+
+	repo, err := filepath.EvalSymlinks(t.TempDir())
+	require.NoError(t, err)
+	repo = filepath.Join(repo, "shop")
+	require.NoError(t, os.MkdirAll(repo, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "README.md"), []byte("# shop\n"), 0o644))
+
+	for _, args := range [][]string{
+		{"init", "-q", "-b", "main"},
+		{"add", "README.md"},
+		{"commit", "-q", "-m", "Initial commit"},
+	} {
+		c := exec.Command("git", args...)
+		c.Dir = repo
+		out, err := c.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+
+	t.Chdir(repo)
+	return repo
+}
+
+func TestWorktreeTarget(t *testing.T) {
+	repo := newTestRepo(t)
+	cfg := config.DefaultConfig()
+
+	t.Run("uses worktrees_dir and name_template from config", func(t *testing.T) {
+		path, err := worktreeTarget(cfg, repo, "", pathtmpl.Vars{Branch: "feature/a", Name: "a", Prefix: "feature/"})
+		require.NoError(t, err)
+		assert.Equal(t, filepath.Join(filepath.Dir(repo), "shop-feature-a"), path)
+	})
+
+	t.Run("--worktrees-dir wins over config", func(t *testing.T) {
+		path, err := worktreeTarget(cfg, repo, "wt", pathtmpl.Vars{Branch: "feature/a", Name: "a", Prefix: "feature/"})
+		require.NoError(t, err)
+		assert.Equal(t, filepath.Join(repo, "wt", "shop-feature-a"), path)
+	})
+
+	t.Run("adds a suffix when the path is taken", func(t *testing.T) {
+		byName := *cfg
+		byName.NameTemplate = "${NAME}"
+		taken := filepath.Join(filepath.Dir(repo), "a")
+		require.NoError(t, os.MkdirAll(taken, 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(taken, "file"), nil, 0o644))
+
+		path, err := worktreeTarget(&byName, repo, "", pathtmpl.Vars{Branch: "bugfix/a", Name: "a", Prefix: "bugfix/"})
+		require.NoError(t, err)
+		assert.Equal(t, taken+"-2", path)
+	})
+}
+
+func TestNewCommand_UsesConfig(t *testing.T) {
+	repo := newTestRepo(t)
+	cfg := config.DefaultConfig()
+	cfg.WorktreesDir = "../trees"
+	cfg.NameTemplate = "${PREFIX}/${NAME}"
+	cfg.DefaultBranchPrefix = "task/"
+	require.NoError(t, config.SaveConfig(repo, cfg))
+
+	rootCmd.SetArgs([]string{"new", "login"})
+	require.NoError(t, rootCmd.Execute())
+
+	path := filepath.Join(filepath.Dir(repo), "trees", "task", "login")
+	assert.DirExists(t, path)
+
+	out, err := exec.Command("git", "-C", path, "branch", "--show-current").Output()
+	require.NoError(t, err)
+	assert.Equal(t, "task/login\n", string(out))
+}
+
+func TestNewCommand_NamingPolicy(t *testing.T) {
+	repo := newTestRepo(t)
+	cfg := config.DefaultConfig()
+	cfg.WorktreesDir = "../trees"
+	cfg.NameTemplate = "${NAME}"
+	cfg.Naming = naming.Policy{Lowercase: true, Slugify: true, TicketPattern: `[A-Z]+-\d+`}
+	require.NoError(t, config.SaveConfig(repo, cfg))
+
+	var buf bytes.Buffer
+	rootCmd.SetOut(&buf)
+	t.Cleanup(func() { rootCmd.SetOut(nil) })
+
+	rootCmd.SetArgs([]string{"new", "proj-7 Crème Brûlée"})
+	require.NoError(t, rootCmd.Execute())
+	assert.Contains(t, buf.String(), `normalized "feature/proj-7 Crème Brûlée" → "feature/PROJ-7-creme-brulee"`)
+	assert.DirExists(t, filepath.Join(filepath.Dir(repo), "trees", "PROJ-7-creme-brulee"))
+
+	rootCmd.SetArgs([]string{"new", "no ticket"})
+	assert.ErrorContains(t, rootCmd.Execute(), "must contain a ticket id")
+
+	rootCmd.SetArgs([]string{"new", "../../x"})
+	assert.ErrorContains(t, rootCmd.Execute(), "must not contain '..'")
+}
@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// errPickCancelled is returned when the user leaves the picker without choosing
+var errPickCancelled = errors.New("cancelled")
+
+// pick lets the user choose one of rows and returns its index. It uses fzf
+// when reading from a terminal and fzf is on PATH, and a numbered prompt on
+// out otherwise. Typing text at the prompt narrows the list.
+func pick(in io.Reader, out io.Writer, rows []string) (int, error) {
+	if in == os.Stdin {
+		if !isTerminal(os.Stdin) {
+			return -1, fmt.Errorf("several matches and no terminal to choose from")
+		}
+		if _, err := exec.LookPath("fzf"); err == nil {
+			return pickFzf(rows)
+		}
+	}
+	return pickPrompt(in, out, rows)
+}
+
+func pickFzf(rows []string) (int, error) {
+	var input strings.Builder
+	for i, r := range rows {
+		fmt.Fprintf(&input, "%d\t%s\n", i, r)
+	}
+	c := exec.Command("fzf", "--height=40%", "--reverse", "--no-sort", "--delimiter=\t", "--with-nth=2..", "--prompt=worktree> ")
+	c.Stdin = strings.NewReader(input.String())
+	c.Stderr = os.Stderr
+	selected, err := c.Output()
+	if err != nil {
+		// 1: no match, 130: interrupted with Esc/Ctrl-C
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && (exitErr.ExitCode() == 1 || exitErr.ExitCode() == 130) {
+			return -1, errPickCancelled
+		}
+		return -1, fmt.Errorf("fzf failed: %w", err)
+	}
+	idx, _, _ := bytes.Cut(selected, []byte("\t"))
+	return strconv.Atoi(string(idx))
+}
+
+func pickPrompt(in io.Reader, out io.Writer, rows []string) (int, error) {
+	reader := bufio.NewReader(in)
+	visible := make([]int, len(rows))
+	for i := range rows {
+		visible[i] = i
+	}
+	for {
+		for n, i := range visible {
+			fmt.Fprintf(out, "%3d) %s\n", n+1, rows[i])
+		}
+		fmt.Fprintf(out, "Select [1-%d], type to filter, empty to cancel: ", len(visible))
+		line, err := reader.ReadString('\n')
+		answer := strings.TrimSpace(line)
+		if answer == "" {
+			return -1, errPickCancelled
+		}
+		if n, convErr := strconv.Atoi(answer); convErr == nil {
+			if n >= 1 && n <= len(visible) {
+				return visible[n-1], nil
+			}
+			fmt.Fprintf(out, "No entry %d.\n", n)
+			continue
+		}
+
+		var narrowed []int
+		for _, i := range visible {
+			if strings.Contains(strings.ToLower(rows[i]), strings.ToLower(answer)) {
+				narrowed = append(narrowed, i)
+			}
+		}
+		switch len(narrowed) {
+		case 0:
+			fmt.Fprintf(out, "Nothing matches %q.\n", answer)
+		case 1:
+			return narrowed[0], nil
+		default:
+			visible = narrowed
+		}
+		if err != nil {
+			return -1, errPickCancelled
+		}
+	}
+}
+
+// isTerminal reports whether f is an interactive terminal
+func isTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/gitwohq/gitwo/internal/config"
+	"github.com/gitwohq/gitwo/internal/hooks"
+	"github.com/gitwohq/gitwo/internal/wt"
+	"github.com/spf13/cobra"
+)
+
+var (
+	pruneDryRun       bool
+	pruneYes          bool
+	pruneInteractive  bool
+	pruneDeleteBranch bool
+	pruneStaleDays    int
+	pruneForce        bool
+	pruneJobs         int
+)
+
+func init() {
+	pruneCmd := &cobra.Command{
+		Use:   "prune",
+		Short: "Remove worktrees whose work is finished",
+		Long: `Find worktrees that look finished, show the plan, and remove them.
+
+A worktree is offered for pruning when its branch
+- is merged into main_branch, including squash and rebase merges,
+- tracks an upstream branch that no longer exists, or
+- has had no commits for --stale days.
+
+Worktrees with uncommitted changes, an operation in progress, or commits
+that are neither on a remote nor in main_branch are never removed; they are
+listed as skipped with the reason. Locked worktrees are skipped too unless
+--force is given.
+
+Examples:
+  gitwo prune --dry-run            # Show what would be removed
+  gitwo prune                      # Show the plan and ask once
+  gitwo prune -i                   # Ask for each worktree
+  gitwo prune --yes --delete-branch
+  gitwo prune --stale 30           # Also prune branches idle for 30 days`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := cmd.OutOrStdout()
+
+			repoPath, err := wt.MainRoot()
+			if err != nil {
+				return err
+			}
+			cfg := loadRepoConfig(cmd.ErrOrStderr(), repoPath)
+			mainBranch, err := wt.ResolveMainBranch(cfg.MainBranch)
+			if err != nil {
+				return err
+			}
+
+			candidates, err := wt.FindPruneCandidates(wt.PruneOptions{
+				MainBranch:    mainBranch,
+				StaleAfter:    time.Duration(pruneStaleDays) * 24 * time.Hour,
+				Workers:       pruneJobs,
+				IncludeLocked: pruneForce,
+			})
+			if err != nil {
+				return err
+			}
+			if len(candidates) == 0 {
+				fmt.Fprintln(out, "Nothing to prune.")
+				return nil
+			}
+
+			var removable []wt.PruneCandidate
+			tw := tabwriter.NewWriter(out, 2, 4, 2, ' ', 0)
+			fmt.Fprintln(tw, "PATH\tBRANCH\tREASON\tACTION")
+			for _, c := range candidates {
+				action := "remove"
+				if pruneDeleteBranch {
+					action = "remove, delete branch"
+				}
+				if c.Blocked != "" {
+					action = "skip: " + c.Blocked
+				} else {
+					removable = append(removable, c)
+				}
+				fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", displayPath(c.Item.Path), c.Item.Branch, strings.Join(c.Reasons, ", "), action)
+			}
+			if err := tw.Flush(); err != nil {
+				return err
+			}
+
+			if pruneDryRun || len(removable) == 0 {
+				return nil
+			}
+
+			in := bufio.NewReader(cmd.InOrStdin())
+			if !pruneYes && !pruneInteractive {
+				if !confirm(in, out, fmt.Sprintf("Remove %d worktree(s)?", len(removable))) {
+					fmt.Fprintln(out, "Aborted.")
+					return nil
+				}
+			}
+
+			var failed int
+			for _, c := range removable {
+				if pruneInteractive && !pruneYes {
+					if !confirm(in, out, fmt.Sprintf("Remove %s (%s)?", displayPath(c.Item.Path), c.Item.Branch)) {
+						continue
+					}
+				}
+				if err := pruneOne(cmd, cfg, repoPath, c); err != nil {
+					fmt.Fprintf(cmd.ErrOrStderr(), "✗ %s: %v\n", displayPath(c.Item.Path), err)
+					failed++
+				}
+			}
+			if failed > 0 {
+				return fmt.Errorf("%d worktree(s) could not be pruned", failed)
+			}
+			return nil
+		},
+	}
+
+	pruneCmd.Flags().BoolVarP(&pruneDryRun, "dry-run", "n", false, "Show what would be removed without removing anything")
+	pruneCmd.Flags().BoolVarP(&pruneYes, "yes", "y", false, "Remove without asking")
+	pruneCmd.Flags().BoolVarP(&pruneInteractive, "interactive", "i", false, "Ask before removing each worktree")
+	pruneCmd.Flags().BoolVar(&pruneDeleteBranch, "delete-branch", false, "Also delete the local branch of each removed worktree")
+	pruneCmd.Flags().BoolVarP(&pruneForce, "force", "f", false, "Also prune locked worktrees (dirty or unpushed ones are still skipped)")
+	pruneCmd.Flags().IntVar(&pruneStaleDays, "stale", 0, "Also prune branches without commits for this many days (0 disables)")
+	pruneCmd.Flags().IntVarP(&pruneJobs, "jobs", "j", 8, "Number of worktrees to inspect in parallel")
+	pruneCmd.Flags().BoolVar(&noHooks, "no-hooks", false, "skip pre_remove/post_remove hooks")
+	rootCmd.AddCommand(pruneCmd)
+}
+
+// pruneOne removes a single candidate, running remove hooks around it
+func pruneOne(cmd *cobra.Command, cfg *config.Config, repoPath string, c wt.PruneCandidate) error {
+	out := cmd.OutOrStdout()
+	// The candidate may be minutes old with -i: check again before the hooks
+	opts := wt.RemoveOptions{IgnoreLock: c.Item.Locked}
+	if err := wt.CheckRemovable(c.Item, opts); err != nil {
+		return err
+	}
+	run := hookRun{
+		Event:        hooks.EventPreRemove,
+		Action:       "prune",
+		RepoPath:     repoPath,
+		Branch:       c.Item.Branch,
+		WorktreePath: c.Item.Path,
+		Dir:          c.Item.Path,
+	}
+	if err := runHooks(out, cfg, run); err != nil {
+		return err
+	}
+
+	if err := wt.RemoveWithOptions(c.Item.Path, opts); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "✓ Removed %s\n", displayPath(c.Item.Path))
+
+	if pruneDeleteBranch {
+		if err := wt.DeleteBranch(c.Item.Branch); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "✓ Deleted branch %s\n", c.Item.Branch)
+	}
+
+	run.Event = hooks.EventPostRemove
+	run.Dir = repoPath
+	if err := runHooks(out, cfg, run); err != nil {
+		return fmt.Errorf("worktree removed, but %w", err)
+	}
+	return nil
+}
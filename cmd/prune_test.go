@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPruneCommand(t *testing.T) {
+	repo := newTestRepo(t)
+	parent := filepath.Dir(repo)
+	git := func(dir string, args ...string) {
+		t.Helper()
+		c := exec.Command("git", args...)
+		c.Dir = dir
+		out, err := c.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	commit := func(dir, name string) {
+		t.Helper()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(name), 0o644))
+		git(dir, "add", name)
+		git(dir, "commit", "-q", "-m", "add "+name)
+	}
+
+	done := filepath.Join(parent, "done")
+	git(repo, "worktree", "add", "-q", "-b", "feature/done", done, "main")
+	commit(done, "done.txt")
+	git(repo, "merge", "-q", "--no-ff", "-m", "Merge feature/done", "feature/done")
+
+	wip := filepath.Join(parent, "wip")
+	git(repo, "worktree", "add", "-q", "-b", "feature/wip", wip, "main")
+	commit(wip, "wip.txt")
+
+	t.Cleanup(func() {
+		pruneDryRun, pruneYes, pruneDeleteBranch = false, false, false
+		rootCmd.SetOut(nil)
+	})
+
+	t.Run("dry run changes nothing", func(t *testing.T) {
+		var out bytes.Buffer
+		rootCmd.SetOut(&out)
+		rootCmd.SetArgs([]string{"prune", "--dry-run"})
+		require.NoError(t, rootCmd.Execute())
+
+		assert.Contains(t, out.String(), "feature/done")
+		assert.Contains(t, out.String(), "merged")
+		assert.NotContains(t, out.String(), "feature/wip")
+		assert.DirExists(t, done)
+	})
+
+	t.Run("removes merged worktrees and their branches", func(t *testing.T) {
+		pruneDryRun = false
+		var out bytes.Buffer
+		rootCmd.SetOut(&out)
+		rootCmd.SetArgs([]string{"prune", "--yes", "--delete-branch"})
+		require.NoError(t, rootCmd.Execute())
+
+		assert.NoDirExists(t, done)
+		assert.DirExists(t, wip)
+		err := exec.Command("git", "-C", repo, "rev-parse", "--verify", "--quiet", "refs/heads/feature/done").Run()
+		assert.Error(t, err, "branch should be deleted")
+	})
+}
@@ -2,11 +2,20 @@ package cmd
 
 import (
 	"fmt"
+	"os"
+	"strings"
 
+	"github.com/gitwohq/gitwo/internal/hooks"
 	"github.com/gitwohq/gitwo/internal/wt"
 	"github.com/spf13/cobra"
 )
 
+var (
+	removeForce        bool
+	removeDeleteBranch bool
+	removeDeleteRemote bool
+)
+
 func init() {
 	removeCmd := &cobra.Command{
 		Use:     "remove <worktree>",
@@ -15,28 +24,116 @@ func init() {
 		Long: `Remove a worktree by path or name.
 
 The worktree can be specified in multiple ways:
-- Full path: gitwo remove ../feature-branch
-- Name only: gitwo remove feature-branch
-- Short alias: gitwo rm feature-branch
+- Path: gitwo remove ../shop-feature-login
+- Directory name: gitwo remove shop-feature-login
+- Branch: gitwo remove feature/login
+- Branch without prefix: gitwo remove login
+- Unique prefix: gitwo rm log
+
+Nothing is removed when the worktree has uncommitted changes, untracked
+files or an operation in progress; gitwo prints what would be lost instead.
+With --delete-branch, commits that are not on any remote (or another branch)
+also block the removal. Locked worktrees (gitwo lock) are refused as well.
+--force overrides these checks.
+
+The worktree you are in can only be removed through the shell wrapper
+(gitwo shell-install), which moves you back to the main worktree.
 
 Examples:
   gitwo remove ../test-feature
   gitwo remove test-feature
-  gitwo rm test-feature`,
+  gitwo rm test-feature --delete-branch
+  gitwo rm test-feature --delete-branch --delete-remote
+  gitwo rm test-feature --force`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			worktree := args[0]
-			
-			fmt.Printf("Removing worktree: %s\n", worktree)
-			
-			if err := wt.Remove(worktree); err != nil {
-				return fmt.Errorf("failed to remove worktree: %w", err)
-			}
-			
-			fmt.Printf("Successfully removed worktree: %s\n", worktree)
+			out := humanOut(cmd)
+
+			repoPath, err := wt.MainRoot()
+			if err != nil {
+				return err
+			}
+			cfg := loadRepoConfig(cmd.ErrOrStderr(), repoPath)
+
+			item, err := wt.Resolve(worktree)
+			if err != nil {
+				return err
+			}
+			absPath := item.Path
+			if absPath == repoPath {
+				return fmt.Errorf("%s is the main worktree and cannot be removed", absPath)
+			}
+
+			// Removing the directory the shell is in needs the wrapper to cd out
+			inside := cwdInside(absPath)
+			if inside && !shellMode {
+				return fmt.Errorf("you are inside %s\ncd to the main worktree (%s) first, or install the shell wrapper (gitwo shell-install) so gitwo can move you there", absPath, repoPath)
+			}
+
+			opts := wt.RemoveOptions{
+				Force:        removeForce,
+				DeleteBranch: removeDeleteBranch || removeDeleteRemote,
+				DeleteRemote: removeDeleteRemote,
+			}
+			// Refuse before the pre_remove hooks have side effects
+			if err := wt.CheckRemovable(item, opts); err != nil {
+				return err
+			}
+			if opts.Force {
+				if check, err := wt.CheckRemoval(item, opts); err == nil {
+					if losses := check.Losses(opts.DeleteBranch); len(losses) > 0 {
+						fmt.Fprintf(out, "⚠️  Discarding: %s\n", strings.Join(losses, ", "))
+					}
+				}
+			}
+
+			run := hookRun{
+				Event:        hooks.EventPreRemove,
+				Action:       "remove",
+				RepoPath:     repoPath,
+				Branch:       item.Branch,
+				WorktreePath: absPath,
+				Dir:          absPath,
+			}
+			if err := runHooks(out, cfg, run); err != nil {
+				return err
+			}
+
+			// Leave the worktree before git deletes it, and have the wrapper
+			// follow once it is gone (even if deleting the branch fails)
+			if inside {
+				if err := os.Chdir(repoPath); err != nil {
+					return err
+				}
+				defer func() {
+					if _, err := os.Stat(absPath); os.IsNotExist(err) {
+						emitCD(cmd, repoPath)
+					}
+				}()
+			}
+
+			fmt.Fprintf(out, "Removing worktree: %s\n", displayPath(absPath))
+
+			if err := wt.RemoveWithOptions(absPath, opts); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(out, "Successfully removed worktree: %s\n", displayPath(absPath))
+
+			run.Event = hooks.EventPostRemove
+			run.Dir = repoPath
+			if err := runHooks(out, cfg, run); err != nil {
+				return fmt.Errorf("worktree removed, but %w", err)
+			}
 			return nil
 		},
 	}
 
+	removeCmd.Flags().BoolVarP(&removeForce, "force", "f", false, "remove even if uncommitted changes or unpushed commits would be lost")
+	removeCmd.Flags().BoolVar(&removeDeleteBranch, "delete-branch", false, "also delete the local branch (git branch -d, or -D with --force)")
+	removeCmd.Flags().BoolVar(&removeDeleteRemote, "delete-remote", false, "also delete the branch's upstream on its remote (implies --delete-branch)")
+	removeCmd.Flags().BoolVar(&shellMode, "shell", false, "print a cd command for the shell wrapper")
+	removeCmd.Flags().BoolVar(&noHooks, "no-hooks", false, "skip pre_remove/post_remove hooks")
 	rootCmd.AddCommand(removeCmd)
 }
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gitwohq/gitwo/internal/config"
+	"github.com/gitwohq/gitwo/internal/wt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemoveCommand_ByNameAfterNew(t *testing.T) {
+	repo := newTestRepo(t)
+	cfg := config.DefaultConfig()
+	cfg.WorktreesDir = ".gitwo/worktrees"
+	cfg.NameTemplate = "${NAME}"
+	require.NoError(t, config.SaveConfig(repo, cfg))
+
+	rootCmd.SetArgs([]string{"new", "checkout"})
+	require.NoError(t, rootCmd.Execute())
+	path := filepath.Join(repo, ".gitwo", "worktrees", "checkout")
+	require.DirExists(t, path)
+
+	rootCmd.SetArgs([]string{"rm", "checkout"})
+	require.NoError(t, rootCmd.Execute())
+	assert.NoDirExists(t, path)
+}
+
+func TestRemoveCommand_RefusesBeforeHooks(t *testing.T) {
+	repo := newTestRepo(t)
+	marker := filepath.Join(repo, "pre-remove-ran")
+	cfg := config.DefaultConfig()
+	cfg.WorktreesDir = ".gitwo/worktrees"
+	cfg.NameTemplate = "${NAME}"
+	cfg.Hooks.PreRemove = []config.Hook{{Type: "command", Command: "touch " + marker, Description: "Tear down"}}
+	require.NoError(t, config.SaveConfig(repo, cfg))
+
+	rootCmd.SetArgs([]string{"new", "dirty"})
+	require.NoError(t, rootCmd.Execute())
+	path := filepath.Join(repo, ".gitwo", "worktrees", "dirty")
+	require.NoError(t, os.WriteFile(filepath.Join(path, "notes.txt"), []byte("draft"), 0o644))
+
+	rootCmd.SetArgs([]string{"rm", "dirty"})
+	var unsafe *wt.UnsafeRemovalError
+	require.ErrorAs(t, rootCmd.Execute(), &unsafe)
+	assert.DirExists(t, path)
+	assert.NoFileExists(t, marker, "pre_remove hooks must not run for a refused removal")
+}
@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/gitwohq/gitwo/internal/pathtmpl"
+	"github.com/gitwohq/gitwo/internal/wt"
+	"github.com/spf13/cobra"
+)
+
+var (
+	renamePrefix string
+	renameRemote bool
+	renameNoMove bool
+	renameForce  bool
+)
+
+func init() {
+	renameCmd := &cobra.Command{
+		Use:   "rename <worktree> <new-name>",
+		Short: "Rename a worktree's branch and move its directory to match",
+		Long: `Rename the branch of a worktree and move the worktree so its directory
+matches name_template again.
+
+<new-name> is turned into a branch exactly like 'gitwo new' does: the prefix
+(default_branch_prefix, or --prefix) is put in front and the naming policy
+applied. With --remote the upstream branch is renamed too: the new name is
+pushed, the old remote branch deleted and the upstream reset. If your shell
+is inside the worktree, the shell wrapper follows it to the new directory.
+
+Examples:
+  gitwo rename foo bar                 # feature/foo -> feature/bar
+  gitwo rename foo bar --remote        # also rename origin/feature/foo
+  gitwo rename foo PROJ-42 --prefix bugfix/ --no-move`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := humanOut(cmd)
+
+			item, err := wt.Resolve(args[0])
+			if err != nil {
+				return err
+			}
+			repoPath, err := wt.MainRoot()
+			if err != nil {
+				return err
+			}
+			if item.Path == repoPath {
+				return fmt.Errorf("%s is the main worktree; rename its branch with 'git branch -m'", item.Path)
+			}
+			if item.Branch == "" {
+				return fmt.Errorf("%s has no branch to rename (detached HEAD)", item.Path)
+			}
+			cfg := loadRepoConfig(cmd.ErrOrStderr(), repoPath)
+
+			nameRes, err := branchName(cmd, out, cfg, args[1], renamePrefix)
+			if err != nil {
+				return err
+			}
+			branch := nameRes.Branch
+			if branch == item.Branch {
+				return fmt.Errorf("%s is already on branch %s", item.Path, branch)
+			}
+
+			var dest string
+			if !renameNoMove {
+				dest, err = templatePath(cfg, repoPath, "", pathtmpl.Vars{Branch: branch, Name: nameRes.Name, Prefix: nameRes.Prefix})
+				if err != nil {
+					return err
+				}
+				if filepath.Clean(dest) == filepath.Clean(item.Path) {
+					dest = ""
+				} else {
+					dest = pathtmpl.Unique(dest, worktreePathTaken)
+				}
+			}
+			// Check the move before touching the branch so nothing is half done
+			if dest != "" {
+				if err := wt.CheckMove(item, dest, wt.MoveOptions{Force: renameForce}); err != nil {
+					return err
+				}
+			}
+
+			res, err := wt.RenameBranch(item.Branch, branch, wt.RenameOptions{Remote: renameRemote})
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(out, "Renamed branch %s to %s\n", item.Branch, branch)
+			if res.Remote != "" {
+				fmt.Fprintf(out, "Renamed %s/%s to %s/%s\n", res.Remote, res.OldRemoteRef, res.Remote, res.NewRemoteRef)
+			}
+
+			if dest == "" {
+				return nil
+			}
+			rel, err := leaveWorktree(item.Path, repoPath)
+			if err != nil {
+				return err
+			}
+			newPath, err := wt.Move(item, dest, wt.MoveOptions{Force: renameForce})
+			if err != nil {
+				return fmt.Errorf("branch renamed, but moving the worktree failed: %w", err)
+			}
+			fmt.Fprintf(out, "Moved %s to %s\n", displayPath(item.Path), displayPath(newPath))
+
+			followWorktree(cmd, out, newPath, rel)
+			return nil
+		},
+	}
+
+	renameCmd.Flags().StringVar(&renamePrefix, "prefix", "", "branch prefix to use, empty to disable (default: default_branch_prefix from config)")
+	renameCmd.Flags().BoolVar(&renameRemote, "remote", false, "rename the upstream branch on its remote too")
+	renameCmd.Flags().BoolVar(&renameNoMove, "no-move", false, "keep the worktree directory where it is")
+	renameCmd.Flags().BoolVarP(&renameForce, "force", "f", false, "move locked worktrees too")
+	renameCmd.Flags().BoolVar(&shellMode, "shell", false, "print a cd command for the shell wrapper")
+	rootCmd.AddCommand(renameCmd)
+}
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gitwohq/gitwo/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenameCommand_MovesDirectoryToTemplate(t *testing.T) {
+	repo := newTestRepo(t)
+	cfg := config.DefaultConfig()
+	cfg.WorktreesDir = ".gitwo/worktrees"
+	cfg.NameTemplate = "${NAME}"
+	require.NoError(t, config.SaveConfig(repo, cfg))
+	t.Cleanup(func() { renameNoMove = false })
+
+	rootCmd.SetArgs([]string{"new", "foo"})
+	require.NoError(t, rootCmd.Execute())
+	oldPath := filepath.Join(repo, ".gitwo", "worktrees", "foo")
+	require.DirExists(t, oldPath)
+
+	rootCmd.SetArgs([]string{"rename", "foo", "bar"})
+	require.NoError(t, rootCmd.Execute())
+
+	newPath := filepath.Join(repo, ".gitwo", "worktrees", "bar")
+	assert.NoDirExists(t, oldPath)
+	require.DirExists(t, newPath)
+	out, err := exec.Command("git", "-C", newPath, "branch", "--show-current").Output()
+	require.NoError(t, err)
+	assert.Equal(t, "feature/bar", strings.TrimSpace(string(out)))
+
+	rootCmd.SetArgs([]string{"rename", "bar", "baz", "--no-move"})
+	require.NoError(t, rootCmd.Execute())
+	out, err = exec.Command("git", "-C", newPath, "branch", "--show-current").Output()
+	require.NoError(t, err)
+	assert.Equal(t, "feature/baz", strings.TrimSpace(string(out)))
+}
+
+func TestRenameCommand_LockedKeepsBranch(t *testing.T) {
+	repo := newTestRepo(t)
+	cfg := config.DefaultConfig()
+	cfg.WorktreesDir = ".gitwo/worktrees"
+	cfg.NameTemplate = "${NAME}"
+	require.NoError(t, config.SaveConfig(repo, cfg))
+
+	rootCmd.SetArgs([]string{"new", "foo"})
+	require.NoError(t, rootCmd.Execute())
+	path := filepath.Join(repo, ".gitwo", "worktrees", "foo")
+	require.NoError(t, exec.Command("git", "-C", repo, "worktree", "lock", path).Run())
+
+	rootCmd.SetArgs([]string{"rename", "foo", "bar"})
+	require.Error(t, rootCmd.Execute())
+
+	out, err := exec.Command("git", "-C", path, "branch", "--show-current").Output()
+	require.NoError(t, err)
+	assert.Equal(t, "feature/foo", strings.TrimSpace(string(out)))
+}
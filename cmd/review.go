@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"fmt"
+	"os/exec"
+	"time"
+
+	"github.com/gitwohq/gitwo/internal/wt"
+	"github.com/spf13/cobra"
+)
+
+var (
+	reviewTTL  string
+	reviewBase string
+	reviewStat bool
+)
+
+func init() {
+	reviewCmd := &cobra.Command{
+		Use:   "review <ref>",
+		Short: "Check out a ref in a temporary worktree and show its diff",
+		Long: `Create a temporary, detached worktree for reviewing or trying out <ref> and
+print its diff against the merge-base with --base (default: main_branch).
+
+Review worktrees live in .gitwo/tmp/<ref>-<id> and expire after --ttl
+(default 2d). Once expired, the next gitwo command (or gitwo gc) removes them
+if they are clean; worktrees with uncommitted changes or new commits are kept
+and reported instead. With the shell wrapper your shell changes into the
+worktree.
+
+Examples:
+  gitwo review origin/feature/login
+  gitwo review v1.4.0 --base v1.3.0 --stat
+  gitwo review 3f2a9c1 --ttl 4h`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := humanOut(cmd)
+			ttl, err := wt.ParseTTL(reviewTTL)
+			if err != nil {
+				return err
+			}
+			repoPath, err := wt.MainRoot()
+			if err != nil {
+				return err
+			}
+			base := reviewBase
+			if base == "" {
+				base = defaultStartPoint(cmd.ErrOrStderr(), loadRepoConfig(cmd.ErrOrStderr(), repoPath))
+			}
+
+			r, err := wt.CreateReview(args[0], base, ttl)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(out, "Review worktree for %s at %s (detached at %s, expires %s)\n",
+				r.Ref, displayPath(r.Path), wt.ShortSHA(r.Head), r.Expires.Format(time.DateTime))
+
+			if r.Base == "" {
+				fmt.Fprintf(out, "note: %s and %s have no common history; no diff to show\n", r.Ref, base)
+			} else {
+				fmt.Fprintf(out, "Changes since the merge-base with %s (%s):\n", base, wt.ShortSHA(r.Base))
+				diffArgs := []string{"-C", r.Path, "diff"}
+				if reviewStat {
+					diffArgs = append(diffArgs, "--stat")
+				}
+				diff := exec.Command("git", append(diffArgs, r.Base, r.Head)...)
+				diff.Stdout = out
+				diff.Stderr = cmd.ErrOrStderr()
+				if err := diff.Run(); err != nil {
+					return fmt.Errorf("git diff failed: %w", err)
+				}
+			}
+
+			emitCD(cmd, r.Path)
+			return nil
+		},
+	}
+
+	reviewCmd.Flags().StringVar(&reviewTTL, "ttl", "2d", "remove the worktree after this long, e.g. 2d, 12h, 90m")
+	reviewCmd.Flags().StringVar(&reviewBase, "base", "", "diff against the merge-base with this ref (default: main_branch, or HEAD)")
+	reviewCmd.Flags().BoolVar(&reviewStat, "stat", false, "show a diffstat instead of the full diff")
+	reviewCmd.Flags().BoolVar(&shellMode, "shell", false, "print a cd command for the shell wrapper")
+	rootCmd.AddCommand(reviewCmd)
+}
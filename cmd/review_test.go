@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gitwohq/gitwo/internal/wt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReviewCommand_DiffAndExpiry(t *testing.T) {
+	repo := newTestRepo(t)
+	gitOutput(t, repo, "checkout", "-q", "-b", "feature/login")
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "login.go"), []byte("package login\n"), 0o644))
+	gitOutput(t, repo, "add", "login.go")
+	gitOutput(t, repo, "commit", "-q", "-m", "add login")
+	gitOutput(t, repo, "checkout", "-q", "main")
+
+	var stdout, stderr bytes.Buffer
+	rootCmd.SetOut(&stdout)
+	rootCmd.SetErr(&stderr)
+	t.Cleanup(func() {
+		rootCmd.SetOut(nil)
+		rootCmd.SetErr(nil)
+		reviewTTL = "2d"
+		reviewBase = ""
+		reviewStat = false
+	})
+
+	rootCmd.SetArgs([]string{"review", "feature/login", "--base", "main", "--ttl", "4h"})
+	require.NoError(t, rootCmd.Execute())
+	assert.Contains(t, stdout.String(), "Review worktree for feature/login")
+	assert.Contains(t, stdout.String(), "+package login")
+
+	entries, err := os.ReadDir(filepath.Join(repo, ".gitwo", "tmp"))
+	require.NoError(t, err)
+	var path string
+	for _, e := range entries {
+		if strings.HasPrefix(e.Name(), "feature-login-") {
+			path = filepath.Join(repo, ".gitwo", "tmp", e.Name())
+		}
+	}
+	require.NotEmpty(t, path)
+
+	// Not expired yet: other commands leave it alone
+	rootCmd.SetArgs([]string{"list"})
+	require.NoError(t, rootCmd.Execute())
+	assert.DirExists(t, path)
+
+	// Expired: the next command removes it in passing
+	require.NoError(t, wt.UpdateMeta(path, func(m *wt.Meta) { m.Ephemeral.Expires = time.Now().Add(-time.Minute) }))
+	stderr.Reset()
+	rootCmd.SetArgs([]string{"list"})
+	require.NoError(t, rootCmd.Execute())
+	assert.NoDirExists(t, path)
+	assert.Contains(t, stderr.String(), "Removed expired worktree")
+}
+
+func TestGCCommand_WarnsAboutDirty(t *testing.T) {
+	newTestRepo(t)
+	r, err := wt.CreateReview("main", "main", time.Hour)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(r.Path, "notes.txt"), []byte("wip\n"), 0o644))
+	require.NoError(t, wt.UpdateMeta(r.Path, func(m *wt.Meta) { m.Ephemeral.Expires = time.Now().Add(-time.Minute) }))
+
+	var buf bytes.Buffer
+	rootCmd.SetOut(&buf)
+	t.Cleanup(func() { rootCmd.SetOut(nil) })
+
+	rootCmd.SetArgs([]string{"gc"})
+	require.NoError(t, rootCmd.Execute())
+	assert.Contains(t, buf.String(), "was kept: 1 untracked file(s)")
+	assert.DirExists(t, r.Path)
+}
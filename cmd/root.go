@@ -32,6 +32,9 @@ func init() {
 		}
 		_ = cmd.Help()
 	}
+	rootCmd.PersistentPreRun = func(cmd *cobra.Command, args []string) {
+		cleanExpiredInPassing(cmd)
+	}
 }
 
 // Execute is the entrypoint called from main
@@ -14,8 +14,8 @@ import (
 var shellInstallShell string
 
 const (
-	beginMarker = "# >>> gitwo shell wrapper >>>"
-	endMarker   = "# <<< gitwo shell wrapper <<<"
+	beginMarker = shell.WrapperBeginMarker
+	endMarker   = shell.WrapperEndMarker
 )
 
 var shellInstallCmd = &cobra.Command{
@@ -41,26 +41,9 @@ After installation, restart your shell or reload the profile:
 			sh = shell.DetectShell()
 		}
 
-		profilePath := shell.GetShellProfilePath(sh)
-		wrapperContent := shell.GenerateWrapper(sh)
-
-		// Create directory if it doesn't exist
-		if err := os.MkdirAll(filepath.Dir(profilePath), 0o755); err != nil {
-			return fmt.Errorf("failed to create profile directory: %w", err)
-		}
-
-		// Read existing content
-		existingContent := readFileOrEmpty(profilePath)
-
-		// Remove existing wrapper block
-		cleanContent := removeBlock(existingContent, beginMarker, endMarker)
-
-		// Add new wrapper block
-		newContent := cleanContent + "\n" + beginMarker + "\n" + wrapperContent + "\n" + endMarker + "\n"
-
-		// Write to file
-		if err := os.WriteFile(profilePath, []byte(newContent), 0o644); err != nil {
-			return fmt.Errorf("failed to write profile: %w", err)
+		profilePath, err := installWrapper(sh)
+		if err != nil {
+			return err
 		}
 
 		fmt.Printf("✅ Installed wrapper into %s\n", profilePath)
@@ -84,6 +67,33 @@ func init() {
 	rootCmd.AddCommand(shellInstallCmd)
 }
 
+// installWrapper writes the wrapper for sh into its profile, replacing an
+// installed one, and returns the profile's path
+func installWrapper(sh string) (string, error) {
+	profilePath := shell.GetShellProfilePath(sh)
+	wrapperContent := shell.GenerateWrapper(sh)
+
+	// Create directory if it doesn't exist
+	if err := os.MkdirAll(filepath.Dir(profilePath), 0o755); err != nil {
+		return "", fmt.Errorf("failed to create profile directory: %w", err)
+	}
+
+	// Read existing content
+	existingContent := readFileOrEmpty(profilePath)
+
+	// Remove existing wrapper block
+	cleanContent := removeBlock(existingContent, beginMarker, endMarker)
+
+	// Add new wrapper block
+	newContent := cleanContent + "\n" + beginMarker + "\n" + wrapperContent + "\n" + endMarker + "\n"
+
+	// Write to file
+	if err := os.WriteFile(profilePath, []byte(newContent), 0o644); err != nil {
+		return "", fmt.Errorf("failed to write profile: %w", err)
+	}
+	return profilePath, nil
+}
+
 // readFileOrEmpty reads a file or returns empty string if it doesn't exist
 func readFileOrEmpty(path string) string {
 	data, err := os.ReadFile(path)
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// shellMode is bound to the --shell flag the shell wrapper passes to the
+// commands in shell.CDCommands. In this mode human-readable output goes to
+// stderr and stdout only carries a final "cd <path>" line for the wrapper.
+var shellMode bool
+
+// humanOut returns where progress and messages should be written
+func humanOut(cmd *cobra.Command) io.Writer {
+	if shellMode {
+		return cmd.ErrOrStderr()
+	}
+	return cmd.OutOrStdout()
+}
+
+// emitCD asks the shell wrapper to change directory; it must be the last
+// thing written to stdout. Without --shell it does nothing.
+func emitCD(cmd *cobra.Command, path string) {
+	if shellMode {
+		fmt.Fprintf(cmd.OutOrStdout(), "cd %s\n", path)
+	}
+}
+
+// cwdInside reports whether the current directory is path or below it
+func cwdInside(path string) bool {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return false
+	}
+	cwd, _ = filepath.EvalSymlinks(cwd)
+	if p, err := filepath.EvalSymlinks(path); err == nil {
+		path = p
+	}
+	rel, err := filepath.Rel(path, cwd)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// leaveWorktree changes to repoPath when the current directory is inside a
+// worktree that is about to be moved. It returns the current directory
+// relative to the worktree, or "" when it was not inside.
+func leaveWorktree(path, repoPath string) (string, error) {
+	if !cwdInside(path) {
+		return "", nil
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	rel := relInside(path, cwd)
+	if err := os.Chdir(repoPath); err != nil {
+		return "", err
+	}
+	return rel, nil
+}
+
+// followWorktree sends the shell after a moved worktree when leaveWorktree
+// found it inside: the wrapper cds there, otherwise a hint is printed
+func followWorktree(cmd *cobra.Command, out io.Writer, newPath, rel string) {
+	if rel == "" {
+		return
+	}
+	follow := filepath.Join(newPath, rel)
+	if shellMode {
+		emitCD(cmd, follow)
+		return
+	}
+	fmt.Fprintf(out, "Your shell is still in the old location; run: cd %s\n", follow)
+}
+
+// relInside returns dir relative to root, resolving symlinks on both
+func relInside(root, dir string) string {
+	if r, err := filepath.EvalSymlinks(root); err == nil {
+		root = r
+	}
+	if d, err := filepath.EvalSymlinks(dir); err == nil {
+		dir = d
+	}
+	rel, err := filepath.Rel(root, dir)
+	if err != nil {
+		return "."
+	}
+	return rel
+}
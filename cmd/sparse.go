@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gitwohq/gitwo/internal/config"
+	"github.com/gitwohq/gitwo/internal/wt"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	sparseCmd := &cobra.Command{
+		Use:   "sparse <worktree> <list|add|set|disable> [dir|profile...]",
+		Short: "Show or change the sparse-checkout of a worktree",
+		Long: `Show or change which directories a sparse worktree checks out.
+
+  list              print the sparse-checkout directories
+  add <dirs...>     check out more directories
+  set <dirs...>     replace the directories (makes a full worktree sparse)
+  disable           check out everything again
+
+Directories may be given as separate arguments or comma-separated, and
+names of sparse_profiles from .gitwo/config.yml expand to their directories.
+Only the given worktree is affected; git keeps the setting per worktree.
+
+Examples:
+  gitwo sparse api list
+  gitwo sparse api add libs/auth
+  gitwo sparse api set web`,
+		Args: cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			item, err := wt.Resolve(args[0])
+			if err != nil {
+				return err
+			}
+			if item.Bare {
+				return fmt.Errorf("%s is a bare repository", item.Path)
+			}
+			out := cmd.OutOrStdout()
+			action, rest := args[1], args[2:]
+
+			var dirs []string
+			if action == "add" || action == "set" {
+				repoPath, err := wt.MainRoot()
+				if err != nil {
+					return err
+				}
+				dirs = expandSparse(loadRepoConfig(cmd.ErrOrStderr(), repoPath), rest)
+				if len(dirs) == 0 {
+					return fmt.Errorf("'gitwo sparse %s' needs at least one directory or profile", action)
+				}
+			} else if len(rest) > 0 {
+				return fmt.Errorf("'gitwo sparse %s' takes no directories", action)
+			}
+
+			switch action {
+			case "list":
+				current, err := wt.SparseDirs(item.Path)
+				if err != nil {
+					return err
+				}
+				if current == nil {
+					fmt.Fprintf(out, "%s is not sparse (full checkout)\n", displayPath(item.Path))
+					return nil
+				}
+				for _, d := range current {
+					fmt.Fprintln(out, d)
+				}
+				return nil
+			case "add":
+				err = wt.SparseAdd(item.Path, dirs)
+			case "set":
+				err = wt.SparseSet(item.Path, dirs)
+			case "disable":
+				err = wt.SparseDisable(item.Path)
+			default:
+				return fmt.Errorf("unknown action %q (use list, add, set or disable)", action)
+			}
+			if err != nil {
+				return err
+			}
+
+			if action == "disable" {
+				fmt.Fprintf(out, "%s now has a full checkout\n", displayPath(item.Path))
+				return nil
+			}
+			current, err := wt.SparseDirs(item.Path)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(out, "%s now checks out: %s\n", displayPath(item.Path), strings.Join(current, ", "))
+			return nil
+		},
+	}
+
+	rootCmd.AddCommand(sparseCmd)
+}
+
+// expandSparse splits comma-separated arguments and replaces the names of
+// sparse_profiles with their directories, dropping duplicates
+func expandSparse(cfg *config.Config, args []string) []string {
+	var dirs []string
+	seen := map[string]bool{}
+	add := func(d string) {
+		d = strings.Trim(strings.TrimSpace(d), "/")
+		if d != "" && !seen[d] {
+			seen[d] = true
+			dirs = append(dirs, d)
+		}
+	}
+	for _, arg := range args {
+		for _, part := range strings.Split(arg, ",") {
+			if profile, ok := cfg.SparseProfiles[strings.TrimSpace(part)]; ok {
+				for _, d := range profile {
+					add(d)
+				}
+				continue
+			}
+			add(part)
+		}
+	}
+	return dirs
+}
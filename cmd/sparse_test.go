@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/gitwohq/gitwo/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCommand_SparseProfile(t *testing.T) {
+	repo := newTestRepo(t)
+	for _, dir := range []string{"services/api", "libs/common", "web"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(repo, dir), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(repo, dir, "file.txt"), []byte(dir), 0o644))
+	}
+	out, err := exec.Command("sh", "-c", "git add . && git commit -q -m monorepo").CombinedOutput()
+	require.NoError(t, err, string(out))
+
+	cfg := config.DefaultConfig()
+	cfg.WorktreesDir = "../trees"
+	cfg.NameTemplate = "${NAME}"
+	cfg.SparseProfiles = map[string][]string{"api": {"services/api", "libs/common"}}
+	require.NoError(t, config.SaveConfig(repo, cfg))
+
+	var buf bytes.Buffer
+	rootCmd.SetOut(&buf)
+	t.Cleanup(func() {
+		rootCmd.SetOut(nil)
+		newSparse = nil
+		listVerbose = false
+	})
+
+	rootCmd.SetArgs([]string{"new", "api-fix", "--sparse", "api"})
+	require.NoError(t, rootCmd.Execute())
+	path := filepath.Join(filepath.Dir(repo), "trees", "api-fix")
+	assert.FileExists(t, filepath.Join(path, "services", "api", "file.txt"))
+	assert.FileExists(t, filepath.Join(path, "libs", "common", "file.txt"))
+	assert.NoDirExists(t, filepath.Join(path, "web"))
+
+	buf.Reset()
+	rootCmd.SetArgs([]string{"list", "--verbose"})
+	require.NoError(t, rootCmd.Execute())
+	assert.Contains(t, buf.String(), "SPARSE")
+	assert.Contains(t, buf.String(), "libs/common,services/api")
+
+	rootCmd.SetArgs([]string{"sparse", "api-fix", "add", "web"})
+	require.NoError(t, rootCmd.Execute())
+	assert.DirExists(t, filepath.Join(path, "web"))
+
+	buf.Reset()
+	rootCmd.SetArgs([]string{"sparse", "api-fix", "list"})
+	require.NoError(t, rootCmd.Execute())
+	assert.Equal(t, "libs/common\nservices/api\nweb\n", buf.String())
+}
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/gitwohq/gitwo/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCommand_Submodules(t *testing.T) {
+	repo := newTestRepo(t)
+	t.Setenv("GIT_CONFIG_COUNT", "1")
+	t.Setenv("GIT_CONFIG_KEY_0", "protocol.file.allow")
+	t.Setenv("GIT_CONFIG_VALUE_0", "always")
+
+	lib := filepath.Join(filepath.Dir(repo), "lib")
+	require.NoError(t, os.MkdirAll(lib, 0o755))
+	for _, script := range []string{
+		"git -C " + lib + " init -q -b main",
+		"echo v1 > " + filepath.Join(lib, "lib.txt") + " && git -C " + lib + " add . && git -C " + lib + " commit -q -m v1",
+		"echo v2 > " + filepath.Join(lib, "lib.txt") + " && git -C " + lib + " commit -q -am v2",
+		"git submodule add -q " + lib + " libs/lib && git commit -q -m 'add lib'",
+	} {
+		out, err := exec.Command("sh", "-c", script).CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.WorktreesDir = "../trees"
+	cfg.NameTemplate = "${NAME}"
+	require.NoError(t, config.SaveConfig(repo, cfg))
+
+	var buf bytes.Buffer
+	rootCmd.SetOut(&buf)
+	t.Cleanup(func() {
+		rootCmd.SetOut(nil)
+		listVerbose = false
+	})
+
+	rootCmd.SetArgs([]string{"new", "login"})
+	require.NoError(t, rootCmd.Execute())
+	path := filepath.Join(filepath.Dir(repo), "trees", "login")
+	assert.FileExists(t, filepath.Join(path, "libs", "lib", "lib.txt"))
+	assert.Contains(t, buf.String(), "Submodules: 1 submodule (1 reusing local objects)")
+
+	buf.Reset()
+	rootCmd.SetArgs([]string{"list", "--verbose"})
+	require.NoError(t, rootCmd.Execute())
+	assert.NotContains(t, buf.String(), "SUBMODULES")
+
+	out, err := exec.Command("git", "-C", filepath.Join(path, "libs", "lib"), "checkout", "-q", "HEAD~1").CombinedOutput()
+	require.NoError(t, err, string(out))
+	buf.Reset()
+	rootCmd.SetArgs([]string{"list", "--verbose"})
+	require.NoError(t, rootCmd.Execute())
+	assert.Contains(t, buf.String(), "SUBMODULES")
+	assert.Contains(t, buf.String(), "drifted: libs/lib")
+}
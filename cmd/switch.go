@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/gitwohq/gitwo/internal/wt"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	switchCmd := &cobra.Command{
+		Use:     "switch [query]",
+		Aliases: []string{"sw"},
+		Short:   "Switch to another worktree",
+		Long: `Switch to another worktree, picking it by (fuzzy) name.
+
+The query is matched against worktree directory names and branches, first
+exactly or by unique prefix (see 'gitwo remove'), then fuzzily: "fl" finds
+feature/login. When several worktrees match, or no query is given, a picker
+shows each worktree with its branch, local changes and last-commit age. It
+uses fzf when it is installed and a numbered prompt otherwise.
+
+With the shell wrapper (gitwo shell-install) your shell changes directory to
+the chosen worktree. Without it the path is printed, so you can run:
+  cd "$(gitwo switch login)"
+
+Examples:
+  gitwo switch               # Pick from all worktrees
+  gitwo switch login         # Go straight to feature/login
+  gitwo sw pay               # Fuzzy match, picker if ambiguous`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			query := ""
+			if len(args) == 1 {
+				query = args[0]
+			}
+
+			items, err := wt.List()
+			if err != nil {
+				return err
+			}
+
+			var target wt.WorktreeItem
+			if query != "" {
+				target, err = wt.ResolveIn(items, query)
+			}
+			if query == "" || err != nil {
+				var ambiguous *wt.AmbiguousError
+				candidates := wt.FuzzyFilter(items, query)
+				if errors.As(err, &ambiguous) {
+					candidates = ambiguous.Candidates
+				}
+				switch len(candidates) {
+				case 0:
+					return err
+				case 1:
+					target = candidates[0]
+				default:
+					i, pickErr := pick(cmd.InOrStdin(), cmd.ErrOrStderr(), switchRows(candidates, wt.CurrentIndex(candidates)))
+					if errors.Is(pickErr, errPickCancelled) {
+						return nil
+					}
+					if pickErr != nil {
+						if err == nil {
+							err = &wt.AmbiguousError{Query: query, Candidates: candidates}
+						}
+						return fmt.Errorf("%w\n%v", err, pickErr)
+					}
+					target = candidates[i]
+				}
+			}
+
+			if _, err := os.Stat(target.Path); err != nil {
+				return fmt.Errorf("worktree directory is missing: %s", target.Path)
+			}
+
+			if shellMode {
+				fmt.Fprintf(cmd.ErrOrStderr(), "→ %s (%s)\n", displayPath(target.Path), target.Label())
+				emitCD(cmd, target.Path)
+				return nil
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), target.Path)
+			if os.Getenv("GITWO_WRAPPER") == "" && isTerminal(os.Stdout) {
+				fmt.Fprintln(cmd.ErrOrStderr(), "tip: run 'gitwo shell-install' so switch changes your directory")
+			}
+			return nil
+		},
+	}
+
+	switchCmd.Flags().BoolVar(&shellMode, "shell", false, "print a cd command for the shell wrapper")
+	rootCmd.AddCommand(switchCmd)
+}
+
+// switchRows renders one aligned picker line per worktree: path, branch,
+// local changes and the age of the last commit
+func switchRows(items []wt.WorktreeItem, current int) []string {
+	statuses := wt.CollectStatus(items, "", 8)
+
+	var buf bytes.Buffer
+	tw := tabwriter.NewWriter(&buf, 2, 4, 2, ' ', 0)
+	for i, it := range items {
+		age := "-"
+		if last, err := wt.LastCommitTime(it.Head); err == nil {
+			age = formatAge(time.Since(last)) + " ago"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", formatPath(displayPath(it.Path), i == current), it.Label(), statuses[i], age)
+	}
+	tw.Flush()
+	return strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+}
+
+// formatAge renders a duration as a short age such as "5m", "3h", "2d" or "6w"
+func formatAge(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return "<1m"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	case d < 14*24*time.Hour:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	case d < 365*24*time.Hour:
+		return fmt.Sprintf("%dw", int(d.Hours()/24/7))
+	default:
+		return fmt.Sprintf("%dy", int(d.Hours()/24/365))
+	}
+}
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"bytes"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSwitchCommand(t *testing.T) {
+	repo := newTestRepo(t)
+	parent := filepath.Dir(repo)
+	for _, b := range []string{"login", "logout", "payments"} {
+		out, err := exec.Command("git", "-C", repo, "worktree", "add", "-q", "-b", "feature/"+b, filepath.Join(parent, b)).CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+
+	run := func(t *testing.T, input string, args ...string) (string, string) {
+		t.Helper()
+		var stdout, stderr bytes.Buffer
+		rootCmd.SetOut(&stdout)
+		rootCmd.SetErr(&stderr)
+		rootCmd.SetIn(strings.NewReader(input))
+		t.Cleanup(func() {
+			rootCmd.SetOut(nil)
+			rootCmd.SetErr(nil)
+			rootCmd.SetIn(nil)
+			shellMode = false
+		})
+		rootCmd.SetArgs(args)
+		require.NoError(t, rootCmd.Execute())
+		return stdout.String(), stderr.String()
+	}
+
+	t.Run("unique match prints the path", func(t *testing.T) {
+		out, _ := run(t, "", "switch", "pay")
+		assert.Equal(t, filepath.Join(parent, "payments")+"\n", out)
+	})
+
+	t.Run("fuzzy match", func(t *testing.T) {
+		out, _ := run(t, "", "switch", "pymts")
+		assert.Equal(t, filepath.Join(parent, "payments")+"\n", out)
+	})
+
+	t.Run("picker for several matches", func(t *testing.T) {
+		out, prompt := run(t, "2\n", "switch", "log")
+		assert.Contains(t, prompt, "feature/login")
+		assert.Contains(t, prompt, "feature/logout")
+		assert.Contains(t, prompt, "CLEAN")
+		assert.Equal(t, filepath.Join(parent, "logout")+"\n", out)
+	})
+
+	t.Run("shell mode hands the path to the wrapper", func(t *testing.T) {
+		out, _ := run(t, "", "switch", "login", "--shell")
+		assert.Equal(t, "cd "+filepath.Join(parent, "login")+"\n", out)
+	})
+}
+
+func TestFormatAge(t *testing.T) {
+	assert.Equal(t, "<1m", formatAge(10*time.Second))
+	assert.Equal(t, "5m", formatAge(5*time.Minute))
+	assert.Equal(t, "3h", formatAge(3*time.Hour))
+	assert.Equal(t, "2d", formatAge(50*time.Hour))
+	assert.Equal(t, "3w", formatAge(21*24*time.Hour))
+}
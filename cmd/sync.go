@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/gitwohq/gitwo/internal/wt"
+	"github.com/spf13/cobra"
+)
+
+var (
+	syncStrategy string
+	syncJobs     int
+	syncNoFetch  bool
+)
+
+func init() {
+	syncCmd := &cobra.Command{
+		Use:   "sync",
+		Short: "Fetch once and update every worktree",
+		Long: `Fetch all remotes once, then update every worktree onto its upstream
+branch, or onto main_branch when it has none. Worktrees are updated in
+parallel.
+
+The strategy (rebase or merge) comes from sync.strategy in
+.gitwo/config.yml and defaults to rebase. Local changes are autostashed.
+Worktrees with a rebase, merge or other operation in progress are skipped.
+When a worktree conflicts, the rebase/merge is aborted so it is left as it
+was, and gitwo exits with a non-zero status.
+
+Examples:
+  gitwo sync
+  gitwo sync --strategy merge
+  gitwo sync --no-fetch -j 4`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := cmd.OutOrStdout()
+
+			repoPath, err := wt.MainRoot()
+			if err != nil {
+				return err
+			}
+			cfg := loadRepoConfig(cmd.ErrOrStderr(), repoPath)
+
+			strategy := cfg.Sync.Strategy
+			if syncStrategy != "" {
+				strategy = syncStrategy
+			}
+			if strategy != "rebase" && strategy != "merge" {
+				return fmt.Errorf("unknown sync strategy %q (use rebase or merge)", strategy)
+			}
+
+			if !syncNoFetch {
+				fmt.Fprintln(out, "Fetching...")
+				if err := wt.Fetch(); err != nil {
+					return err
+				}
+			}
+
+			items, err := wt.List()
+			if err != nil {
+				return err
+			}
+			mainBranch, _ := wt.ResolveMainBranch(cfg.MainBranch)
+			results := wt.SyncAll(items, wt.SyncOptions{
+				Strategy:   strategy,
+				MainBranch: mainBranch,
+				Workers:    syncJobs,
+			})
+
+			counts := map[string]int{}
+			tw := tabwriter.NewWriter(out, 2, 4, 2, ' ', 0)
+			fmt.Fprintln(tw, "PATH\tBRANCH\tONTO\tRESULT\tDETAIL")
+			for _, r := range results {
+				counts[r.Result]++
+				onto := r.Onto
+				if onto == "" {
+					onto = "-"
+				}
+				fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", displayPath(r.Item.Path), r.Item.Label(), onto, r.Result, r.Detail)
+			}
+			if err := tw.Flush(); err != nil {
+				return err
+			}
+			fmt.Fprintf(out, "\n%d updated, %d up-to-date, %d conflicted, %d skipped, %d failed\n",
+				counts[wt.SyncUpdated], counts[wt.SyncUpToDate], counts[wt.SyncConflicted], counts[wt.SyncSkipped], counts[wt.SyncFailed])
+
+			if n := counts[wt.SyncConflicted] + counts[wt.SyncFailed]; n > 0 {
+				return fmt.Errorf("%d worktree(s) could not be synced", n)
+			}
+			return nil
+		},
+	}
+
+	syncCmd.Flags().StringVar(&syncStrategy, "strategy", "", "rebase or merge (default: sync.strategy from config, or rebase)")
+	syncCmd.Flags().IntVarP(&syncJobs, "jobs", "j", 4, "Number of worktrees to update in parallel")
+	syncCmd.Flags().BoolVar(&syncNoFetch, "no-fetch", false, "Skip fetching remotes first")
+	rootCmd.AddCommand(syncCmd)
+}
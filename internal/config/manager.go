@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 
+	"github.com/gitwohq/gitwo/internal/naming"
 	"gopkg.in/yaml.v3"
 )
 
@@ -28,6 +30,20 @@ type Config struct {
 
 	// Hook configuration
 	Hooks HooksConfig `yaml:"hooks"`
+
+	// Sync configuration
+	Sync SyncConfig `yaml:"sync"`
+
+	// Branch naming policy for gitwo new and rename
+	Naming naming.Policy `yaml:"naming,omitempty"`
+
+	// Named sets of sparse-checkout directories for gitwo new --sparse
+	SparseProfiles map[string][]string `yaml:"sparse_profiles,omitempty"`
+}
+
+// SyncConfig controls how `gitwo sync` updates worktrees
+type SyncConfig struct {
+	Strategy string `yaml:"strategy"` // rebase or merge
 }
 
 // ShellConfig represents shell-specific configuration
@@ -39,9 +55,28 @@ type ShellConfig struct {
 
 // HooksConfig represents hook configuration
 type HooksConfig struct {
-	Enabled bool   `yaml:"enabled"`
-	PreAdd  []Hook `yaml:"pre_add"`
-	PostAdd []Hook `yaml:"post_add"`
+	Enabled    bool   `yaml:"enabled"`
+	PreAdd     []Hook `yaml:"pre_add"`
+	PostAdd    []Hook `yaml:"post_add"`
+	PreRemove  []Hook `yaml:"pre_remove,omitempty"`
+	PostRemove []Hook `yaml:"post_remove,omitempty"`
+}
+
+// ForEvent returns the inline hooks configured for a lifecycle event
+// (pre_add, post_add, pre_remove, post_remove).
+func (h HooksConfig) ForEvent(event string) []Hook {
+	switch event {
+	case "pre_add":
+		return h.PreAdd
+	case "post_add":
+		return h.PostAdd
+	case "pre_remove":
+		return h.PreRemove
+	case "post_remove":
+		return h.PostRemove
+	default:
+		return nil
+	}
 }
 
 // Hook represents a single hook
@@ -74,6 +109,9 @@ func DefaultConfig() *Config {
 			PreAdd:  []Hook{},
 			PostAdd: []Hook{},
 		},
+		Sync: SyncConfig{
+			Strategy: "rebase",
+		},
 	}
 }
 
@@ -92,8 +130,10 @@ func LoadConfig(repoPath string) (*Config, error) {
 		return DefaultConfig(), fmt.Errorf("failed to read config: %w", err)
 	}
 
-	// Parse YAML
-	var config Config
+	// Parse YAML on top of the defaults so that keys missing from the file
+	// keep their default values while explicit ones (e.g. hooks.enabled: false)
+	// are preserved
+	config := *DefaultConfig()
 	if err := yaml.Unmarshal(data, &config); err != nil {
 		return DefaultConfig(), fmt.Errorf("failed to parse config: %w", err)
 	}
@@ -128,6 +168,61 @@ func SaveConfig(repoPath string, config *Config) error {
 	return nil
 }
 
+// UpdateConfig sets top-level keys in an existing .gitwo/config.yml and
+// leaves the rest of the file, comments and key order included, as it is.
+// It returns an error wrapping os.ErrNotExist when there is no config file.
+func UpdateConfig(repoPath string, values map[string]string) error {
+	configPath := filepath.Join(repoPath, ".gitwo", "config.yml")
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to read config: %w", err)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("failed to parse config: %w", err)
+	}
+	if doc.Kind == 0 {
+		// An empty file
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return fmt.Errorf("failed to update config: %s is not a mapping", configPath)
+	}
+
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		value := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: values[k]}
+		found := false
+		for i := 0; i+1 < len(root.Content); i += 2 {
+			if root.Content[i].Value == k {
+				// Keep the comments attached to the old value
+				value.LineComment = root.Content[i+1].LineComment
+				root.Content[i+1] = value
+				found = true
+				break
+			}
+		}
+		if !found {
+			root.Content = append(root.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: k}, value)
+		}
+	}
+
+	out, err := yaml.Marshal(&doc)
+	if err != nil {
+		return fmt.Errorf("failed to marshal config: %w", err)
+	}
+	if err := os.WriteFile(configPath, out, 0o644); err != nil {
+		return fmt.Errorf("failed to write config: %w", err)
+	}
+	return nil
+}
+
 // mergeWithDefaults merges config with default values for missing fields
 func mergeWithDefaults(config Config) Config {
 	defaults := DefaultConfig()
@@ -159,14 +254,24 @@ func mergeWithDefaults(config Config) Config {
 		config.Shell.Type = defaults.Shell.Type
 	}
 
-	// Merge hooks config
-	if !config.Hooks.Enabled {
-		config.Hooks.Enabled = defaults.Hooks.Enabled
+	// Merge sync config
+	if config.Sync.Strategy == "" {
+		config.Sync.Strategy = defaults.Sync.Strategy
 	}
 
 	return config
 }
 
+// HookVars returns the configuration values exposed to hooks as GITWO_* variables
+func (c *Config) HookVars() map[string]string {
+	return map[string]string{
+		"worktrees_dir": c.WorktreesDir,
+		"main_branch":   c.MainBranch,
+		"name_template": c.NameTemplate,
+		"editor_cmd":    c.EditorCmd,
+	}
+}
+
 // GetEnvConfig returns configuration from environment variables
 func GetEnvConfig() map[string]string {
 	envConfig := make(map[string]string)
@@ -225,3 +225,38 @@ func TestDefaultConfig(t *testing.T) {
 	assert.Equal(t, "unknown", config.Framework)
 	assert.True(t, config.Hooks.Enabled)
 }
+
+func TestLoadConfig_HooksDisabled(t *testing.T) {
+	tempDir := t.TempDir()
+	gitwoDir := filepath.Join(tempDir, ".gitwo")
+	assert.NoError(t, os.MkdirAll(gitwoDir, 0o755))
+
+	content := `hooks:
+  enabled: false
+  pre_remove:
+    - type: "command"
+      command: "docker compose down"
+      description: "Stop containers"`
+	assert.NoError(t, os.WriteFile(filepath.Join(gitwoDir, "config.yml"), []byte(content), 0o644))
+
+	config, err := LoadConfig(tempDir)
+	assert.NoError(t, err)
+	assert.False(t, config.Hooks.Enabled)
+	assert.Len(t, config.Hooks.ForEvent("pre_remove"), 1)
+	assert.Empty(t, config.Hooks.ForEvent("post_remove"))
+	assert.Equal(t, "..", config.WorktreesDir)
+}
+
+func TestUpdateConfig(t *testing.T) {
+	repoPath := t.TempDir()
+	assert.ErrorIs(t, UpdateConfig(repoPath, map[string]string{"worktrees_dir": "."}), os.ErrNotExist)
+
+	configPath := filepath.Join(repoPath, ".gitwo", "config.yml")
+	assert.NoError(t, os.MkdirAll(filepath.Dir(configPath), 0o755))
+	assert.NoError(t, os.WriteFile(configPath, []byte("# team settings\nworktrees_dir: .. # next to the repo\nauto_switch: true\n"), 0o644))
+
+	assert.NoError(t, UpdateConfig(repoPath, map[string]string{"worktrees_dir": ".", "name_template": "${BRANCH}"}))
+	data, err := os.ReadFile(configPath)
+	assert.NoError(t, err)
+	assert.Equal(t, "# team settings\nworktrees_dir: . # next to the repo\nauto_switch: true\nname_template: ${BRANCH}\n", string(data))
+}
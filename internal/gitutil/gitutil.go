@@ -4,7 +4,6 @@ import (
 	"bytes"
 	"fmt"
 	"os/exec"
-	"path/filepath"
 	"strings"
 )
 
@@ -23,6 +22,15 @@ func BranchExists(name string) bool {
 	return cmd.Run() == nil
 }
 
+// ResolveCommit returns the commit a ref (branch, tag, sha, ...) points to.
+func ResolveCommit(ref string) (string, error) {
+	out, err := exec.Command("git", "rev-parse", "--verify", "--quiet", ref+"^{commit}").Output()
+	if err != nil {
+		return "", fmt.Errorf("%q does not name a commit", ref)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
 // CurrentBranch returns the current branch name or empty string when detached.
 func CurrentBranch() string {
 	out, _ := exec.Command("git", "symbolic-ref", "-q", "--short", "HEAD").Output()
@@ -39,12 +47,6 @@ func IsDetachedHEAD() bool {
 	return exec.Command("git", "rev-parse", "--verify", "HEAD").Run() == nil
 }
 
-// InferDefaultWorktreePath builds default worktree path as <worktreesDir>/<base>.
-func InferDefaultWorktreePath(worktreesDir, branchOrName string) string {
-	base := filepath.Base(branchOrName)
-	return filepath.Join(worktreesDir, base)
-}
-
 // GitWorktreeAdd executes `git worktree add` with given args and returns combined output on error.
 func GitWorktreeAdd(args ...string) error {
 	cmd := exec.Command("git", append([]string{"worktree", "add"}, args...)...)
@@ -11,6 +11,14 @@ import (
 	"gopkg.in/yaml.v3"
 )
 
+// Lifecycle events that hooks can be registered for
+const (
+	EventPreAdd     = "pre_add"
+	EventPostAdd    = "post_add"
+	EventPreRemove  = "pre_remove"
+	EventPostRemove = "post_remove"
+)
+
 // Hook represents a single hook
 type Hook struct {
 	Type        string `yaml:"type"`
@@ -39,7 +47,12 @@ func LoadHooks(repoPath, hookType string) ([]Hook, error) {
 		Hooks []Hook `yaml:"hooks"`
 	}
 	if err := yaml.Unmarshal(data, &hookFile); err != nil {
-		return nil, fmt.Errorf("failed to parse hooks: %w", err)
+		// config.SaveHooks (used by `gitwo init`) writes a bare list
+		var list []Hook
+		if listErr := yaml.Unmarshal(data, &list); listErr != nil {
+			return nil, fmt.Errorf("failed to parse hooks: %w", err)
+		}
+		hookFile.Hooks = list
 	}
 
 	// Validate hooks
@@ -54,8 +67,14 @@ func LoadHooks(repoPath, hookType string) ([]Hook, error) {
 
 // ExecuteHooks executes a list of hooks with the given environment variables
 func ExecuteHooks(hooks []Hook, env map[string]string) error {
+	return ExecuteHooksInDir(hooks, env, "")
+}
+
+// ExecuteHooksInDir executes a list of hooks from the given working directory.
+// An empty dir runs the hooks from the current directory.
+func ExecuteHooksInDir(hooks []Hook, env map[string]string, dir string) error {
 	for _, hook := range hooks {
-		if err := ExecuteHook(hook, env); err != nil {
+		if err := executeHook(hook, env, dir); err != nil {
 			return fmt.Errorf("hook execution failed: %w", err)
 		}
 	}
@@ -64,16 +83,20 @@ func ExecuteHooks(hooks []Hook, env map[string]string) error {
 
 // ExecuteHook executes a single hook
 func ExecuteHook(hook Hook, env map[string]string) error {
+	return executeHook(hook, env, "")
+}
+
+func executeHook(hook Hook, env map[string]string, dir string) error {
 	switch hook.Type {
 	case "command":
-		return executeCommandHook(hook, env)
+		return executeCommandHook(hook, env, dir)
 	default:
 		return fmt.Errorf("unknown hook type: %s", hook.Type)
 	}
 }
 
 // executeCommandHook executes a command hook
-func executeCommandHook(hook Hook, env map[string]string) error {
+func executeCommandHook(hook Hook, env map[string]string, dir string) error {
 	// Get timeout from environment
 	timeout := 30 * time.Second // default timeout
 	if timeoutStr := env["GITWO_TIMEOUT"]; timeoutStr != "" {
@@ -82,8 +105,11 @@ func executeCommandHook(hook Hook, env map[string]string) error {
 		}
 	}
 
-	// Create command
-	cmd := exec.Command("sh", "-c", hook.Command)
+	// Create command with timeout
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "sh", "-c", hook.Command)
+	cmd.Dir = dir
 
 	// Set environment variables
 	cmd.Env = os.Environ()
@@ -91,11 +117,6 @@ func executeCommandHook(hook Hook, env map[string]string) error {
 		cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", key, value))
 	}
 
-	// Set timeout
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
-	defer cancel()
-	cmd = exec.CommandContext(ctx, "sh", "-c", hook.Command)
-
 	// Execute command
 	output, err := cmd.CombinedOutput()
 	if err != nil {
@@ -125,10 +146,16 @@ func ValidateHook(hook Hook) error {
 
 // CreateHookEnvironment creates environment variables for hook execution
 func CreateHookEnvironment(repoPath, branch, worktreePath string, config map[string]string) map[string]string {
+	return CreateActionEnvironment("add", repoPath, branch, worktreePath, config)
+}
+
+// CreateActionEnvironment creates environment variables for hook execution,
+// recording the gitwo action (new, add, remove, ...) in GITWO_ACTION.
+func CreateActionEnvironment(action, repoPath, branch, worktreePath string, config map[string]string) map[string]string {
 	env := make(map[string]string)
 
 	// Core gitwo variables
-	env["GITWO_ACTION"] = "add"
+	env["GITWO_ACTION"] = action
 	env["GITWO_REPO"] = filepath.Base(repoPath)
 	env["GITWO_BRANCH"] = branch
 	env["GITWO_PATH"] = worktreePath
@@ -209,3 +209,56 @@ func TestHookValidation(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadHooks_BareList(t *testing.T) {
+	// `gitwo init` writes hook files through config.SaveHooks as a bare list
+	tempDir := t.TempDir()
+	hooksDir := filepath.Join(tempDir, ".gitwo", "hooks")
+	assert.NoError(t, os.MkdirAll(hooksDir, 0o755))
+
+	content := `- type: command
+  command: bundle install
+  description: Install Ruby dependencies
+- type: command
+  command: bin/setup
+  description: Run Rails setup script
+`
+	assert.NoError(t, os.WriteFile(filepath.Join(hooksDir, "post_add.yml"), []byte(content), 0o644))
+
+	hooks, err := LoadHooks(tempDir, EventPostAdd)
+	assert.NoError(t, err)
+	assert.Len(t, hooks, 2)
+	assert.Equal(t, "bin/setup", hooks[1].Command)
+}
+
+func TestExecuteHooksInDir(t *testing.T) {
+	dir := t.TempDir()
+	hooks := []Hook{
+		{
+			Type:    "command",
+			Command: `printf '%s %s' "$GITWO_ACTION" "$GITWO_BRANCH" > marker.txt`,
+		},
+	}
+	env := CreateActionEnvironment("new", "/repo/app", "feature/x", dir, map[string]string{})
+
+	err := ExecuteHooksInDir(hooks, env, dir)
+	assert.NoError(t, err)
+
+	data, err := os.ReadFile(filepath.Join(dir, "marker.txt"))
+	assert.NoError(t, err)
+	assert.Equal(t, "new feature/x", string(data))
+}
+
+func TestCreateActionEnvironment(t *testing.T) {
+	env := CreateActionEnvironment("remove", "/repo/app", "feature/x", "/repo/app-x", map[string]string{
+		"main_branch": "origin/main",
+	})
+
+	assert.Equal(t, "remove", env["GITWO_ACTION"])
+	assert.Equal(t, "app", env["GITWO_REPO"])
+	assert.Equal(t, "/repo/app-x", env["GITWO_PATH"])
+	assert.Equal(t, "origin/main", env["GITWO_MAIN_BRANCH"])
+
+	// The legacy helper keeps reporting the add action
+	assert.Equal(t, "add", CreateHookEnvironment("/repo/app", "b", "/p", nil)["GITWO_ACTION"])
+}
@@ -0,0 +1,250 @@
+package naming
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Policy is the branch naming policy from the naming: section of
+// .gitwo/config.yml. The zero value only validates names.
+type Policy struct {
+	Lowercase bool `yaml:"lowercase"`  // lowercase names (ticket ids keep their case)
+	Slugify   bool `yaml:"slugify"`    // spaces and unicode become ASCII dashes
+	MaxLength int  `yaml:"max_length"` // maximum length of the full branch name, 0 for no limit
+	// TicketPattern is a regular expression every name must contain a match
+	// of, e.g. "[A-Z]+-\\d+"
+	TicketPattern   string   `yaml:"ticket_pattern"`
+	AllowedPrefixes []string `yaml:"allowed_prefixes"` // e.g. feature/, fix/, chore/; empty allows any
+}
+
+// Result is a branch name after the policy was applied
+type Result struct {
+	Requested string   // prefix and name as given
+	Branch    string   // full branch name, prefix included
+	Prefix    string   // e.g. feature/
+	Name      string   // normalized name without the prefix
+	Changes   []string // what normalization did, e.g. "lowercase"
+}
+
+// Changed reports whether the branch differs from what was requested
+func (r Result) Changed() bool {
+	return r.Branch != r.Requested
+}
+
+// Note describes the normalization for the user, or "" when nothing changed
+func (r Result) Note() string {
+	if !r.Changed() {
+		return ""
+	}
+	note := fmt.Sprintf("normalized %q → %q", r.Requested, r.Branch)
+	if len(r.Changes) > 0 {
+		note += fmt.Sprintf(" (%s)", strings.Join(r.Changes, ", "))
+	}
+	return note
+}
+
+// Apply builds the branch for name with prefix. A name that already starts
+// with one of the allowed prefixes keeps that prefix instead ("fix/login"
+// stays fix/login). The name is normalized as configured and the result
+// validated against the policy and git's ref name rules.
+func (p Policy) Apply(prefix, name string) (Result, error) {
+	res := Result{Requested: prefix + name, Prefix: prefix}
+	if strings.TrimSpace(name) == "" {
+		return res, fmt.Errorf("name cannot be empty")
+	}
+	if err := checkTraversal(name); err != nil {
+		return res, err
+	}
+	for _, allowed := range p.AllowedPrefixes {
+		if allowed != "" && strings.HasPrefix(name, allowed) && len(name) > len(allowed) {
+			res.Requested, res.Prefix = name, allowed
+			name = strings.TrimPrefix(name, allowed)
+			break
+		}
+	}
+
+	var ticket *regexp.Regexp
+	if p.TicketPattern != "" {
+		re, err := regexp.Compile(p.TicketPattern)
+		if err != nil {
+			return res, fmt.Errorf("invalid naming.ticket_pattern %q: %w", p.TicketPattern, err)
+		}
+		ticket = re
+	}
+
+	normalized := name
+	if p.Slugify {
+		if s := Slugify(normalized); s != normalized {
+			normalized = s
+			res.Changes = append(res.Changes, "slugify")
+		}
+	}
+	if p.Lowercase {
+		if s := lowercaseKeeping(normalized, ticket); s != normalized {
+			normalized = s
+			res.Changes = append(res.Changes, "lowercase")
+		}
+	}
+	res.Name = normalized
+	res.Branch = res.Prefix + normalized
+
+	if len(p.AllowedPrefixes) > 0 && !contains(p.AllowedPrefixes, res.Prefix) {
+		return res, fmt.Errorf("branch prefix %q is not allowed by the naming policy (allowed: %s)", res.Prefix, strings.Join(p.AllowedPrefixes, ", "))
+	}
+	if ticket != nil && !ticket.MatchString(normalized) {
+		return res, fmt.Errorf("branch name %q must contain a ticket id matching %s, e.g. 'gitwo new PROJ-123-%s'", res.Branch, p.TicketPattern, normalized)
+	}
+	if p.MaxLength > 0 && len(res.Branch) > p.MaxLength {
+		return res, fmt.Errorf("branch name %q is %d characters long; the naming policy allows at most %d", res.Branch, len(res.Branch), p.MaxLength)
+	}
+	if err := CheckRefFormat(res.Branch); err != nil {
+		return res, err
+	}
+	return res, nil
+}
+
+// checkTraversal rejects names that would climb out of the worktrees
+// directory or are absolute paths
+func checkTraversal(name string) error {
+	if strings.HasPrefix(name, "/") || strings.HasPrefix(name, "\\") || strings.HasPrefix(name, "~") {
+		return fmt.Errorf("invalid name %q: must not be a path", name)
+	}
+	for _, part := range strings.FieldsFunc(name, func(r rune) bool { return r == '/' || r == '\\' }) {
+		if part == ".." || part == "." {
+			return fmt.Errorf("invalid name %q: must not contain '%s' path segments", name, part)
+		}
+	}
+	return nil
+}
+
+// CheckRefFormat validates a branch name with the rules of
+// `git check-ref-format --branch`
+func CheckRefFormat(branch string) error {
+	invalid := func(reason string) error {
+		return fmt.Errorf("invalid branch name %q: %s", branch, reason)
+	}
+	switch {
+	case branch == "":
+		return invalid("empty")
+	case branch == "@":
+		return invalid("cannot be '@'")
+	case strings.HasPrefix(branch, "-"):
+		return invalid("cannot start with '-'")
+	case strings.HasSuffix(branch, "/"):
+		return invalid("cannot end with '/'")
+	case strings.HasSuffix(branch, "."):
+		return invalid("cannot end with '.'")
+	case strings.Contains(branch, ".."):
+		return invalid("cannot contain '..'")
+	case strings.Contains(branch, "@{"):
+		return invalid("cannot contain '@{'")
+	case strings.Contains(branch, "//"):
+		return invalid("cannot contain '//'")
+	}
+	for _, r := range branch {
+		switch {
+		case r < 0x20 || r == 0x7f:
+			return invalid("cannot contain control characters")
+		case r == ' ':
+			return invalid("cannot contain spaces")
+		case strings.ContainsRune(`~^:?*[\`, r):
+			return invalid(fmt.Sprintf("cannot contain '%c'", r))
+		}
+	}
+	for _, part := range strings.Split(branch, "/") {
+		if strings.HasPrefix(part, ".") {
+			return invalid("path components cannot start with '.'")
+		}
+		if strings.HasSuffix(part, ".lock") {
+			return invalid("path components cannot end with '.lock'")
+		}
+	}
+	return nil
+}
+
+// transliterations maps common Latin letters with diacritics to ASCII
+var transliterations = map[rune]string{
+	'à': "a", 'á': "a", 'â': "a", 'ã': "a", 'ä': "a", 'å': "a", 'ā': "a", 'ą': "a",
+	'ç': "c", 'ć': "c", 'č': "c",
+	'ď': "d", 'đ': "d", 'ð': "d",
+	'è': "e", 'é': "e", 'ê': "e", 'ë': "e", 'ē': "e", 'ę': "e", 'ě': "e",
+	'ì': "i", 'í': "i", 'î': "i", 'ï': "i", 'ī': "i", 'ı': "i",
+	'ł': "l", 'ľ': "l",
+	'ñ': "n", 'ń': "n", 'ň': "n",
+	'ò': "o", 'ó': "o", 'ô': "o", 'õ': "o", 'ö': "o", 'ø': "o", 'ō': "o", 'ő': "o",
+	'ř': "r",
+	'ś': "s", 'š': "s", 'ş': "s", 'ß': "ss",
+	'ť': "t", 'ţ': "t", 'þ': "th",
+	'ù': "u", 'ú': "u", 'û': "u", 'ü': "u", 'ū': "u", 'ů': "u", 'ű': "u",
+	'ý': "y", 'ÿ': "y",
+	'ź': "z", 'ż': "z", 'ž': "z",
+	'æ': "ae", 'œ': "oe",
+}
+
+// Slugify turns a free-form name into ASCII suitable for a branch: letters
+// are transliterated, whitespace and other characters become dashes, runs of
+// dashes collapse and path components lose leading and trailing dashes and
+// dots. Slashes and letter case are kept.
+func Slugify(name string) string {
+	var b strings.Builder
+	for _, r := range name {
+		switch {
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r) || r == '/' || r == '.' || r == '_' || r == '-'):
+			b.WriteRune(r)
+		case transliterations[unicode.ToLower(r)] != "":
+			t := transliterations[unicode.ToLower(r)]
+			if unicode.IsUpper(r) {
+				t = strings.ToUpper(t[:1]) + t[1:]
+			}
+			b.WriteString(t)
+		default:
+			b.WriteByte('-')
+		}
+	}
+
+	parts := strings.Split(b.String(), "/")
+	kept := parts[:0]
+	for _, part := range parts {
+		for strings.Contains(part, "--") {
+			part = strings.ReplaceAll(part, "--", "-")
+		}
+		for strings.Contains(part, "..") {
+			part = strings.ReplaceAll(part, "..", ".")
+		}
+		part = strings.Trim(part, "-.")
+		part = strings.TrimSuffix(part, ".lock")
+		if part != "" {
+			kept = append(kept, part)
+		}
+	}
+	return strings.Join(kept, "/")
+}
+
+// lowercaseKeeping lowercases s except for matches of ticket. Ticket ids
+// typed in lower case ("proj-12") are upper-cased when the pattern only
+// matches them that way.
+func lowercaseKeeping(s string, ticket *regexp.Regexp) string {
+	if ticket == nil {
+		return strings.ToLower(s)
+	}
+	if loc := ticket.FindStringIndex(s); loc != nil {
+		return strings.ToLower(s[:loc[0]]) + s[loc[0]:loc[1]] + strings.ToLower(s[loc[1]:])
+	}
+	// Try the upper-cased name, which is how ticket ids are usually written
+	upper := strings.ToUpper(s)
+	if loc := ticket.FindStringIndex(upper); loc != nil && len(upper) == len(s) {
+		return strings.ToLower(s[:loc[0]]) + upper[loc[0]:loc[1]] + strings.ToLower(s[loc[1]:])
+	}
+	return strings.ToLower(s)
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,96 @@
+package naming
+
+import (
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"login", "login"},
+		{"Fix Login Page", "Fix-Login-Page"},
+		{"crème brûlée", "creme-brulee"},
+		{"Straße über Łódź", "Strasse-uber-Lodz"},
+		{"auth/  oidc ", "auth/oidc"},
+		{"fix: crash!!", "fix-crash"},
+		{"a..b", "a.b"},
+		{"--x--", "x"},
+		{"名前 test", "test"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Slugify(tt.input))
+		})
+	}
+}
+
+func TestPolicyApply(t *testing.T) {
+	strict := Policy{
+		Lowercase:       true,
+		Slugify:         true,
+		MaxLength:       40,
+		TicketPattern:   `[A-Z]+-\d+`,
+		AllowedPrefixes: []string{"feature/", "fix/", "chore/"},
+	}
+
+	tests := []struct {
+		name         string
+		policy       Policy
+		prefix, in   string
+		branch, note string
+		err          string
+	}{
+		{name: "zero policy keeps names", prefix: "feature/", in: "login", branch: "feature/login"},
+		{name: "zero policy still validates", prefix: "feature/", in: "my login", err: "cannot contain spaces"},
+		{name: "path traversal", prefix: "feature/", in: "../../x", err: "must not contain '..'"},
+		{name: "absolute path", prefix: "", in: "/etc/passwd", err: "must not be a path"},
+		{
+			name: "slugify and lowercase", policy: Policy{Slugify: true, Lowercase: true},
+			prefix: "feature/", in: "Fix Login Page", branch: "feature/fix-login-page",
+			note: `normalized "feature/Fix Login Page" → "feature/fix-login-page" (slugify, lowercase)`,
+		},
+		{name: "ticket keeps its case", policy: strict, prefix: "feature/", in: "PROJ-12 Login", branch: "feature/PROJ-12-login"},
+		{name: "lower-case ticket is upper-cased", policy: strict, prefix: "feature/", in: "proj-12 login", branch: "feature/PROJ-12-login"},
+		{name: "missing ticket", policy: strict, prefix: "feature/", in: "login", err: "must contain a ticket id"},
+		{name: "allowed prefix in name", policy: strict, prefix: "feature/", in: "fix/ABC-1-crash", branch: "fix/ABC-1-crash"},
+		{name: "prefix not allowed", policy: strict, prefix: "hack/", in: "ABC-1", err: `prefix "hack/" is not allowed`},
+		{name: "too long", policy: strict, prefix: "feature/", in: "ABC-1-" + "abcdefghijklmnopqrstuvwxyz0123456789", err: "at most 40"},
+		{name: "bad ticket pattern", policy: Policy{TicketPattern: "["}, prefix: "", in: "x", err: "invalid naming.ticket_pattern"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := tt.policy.Apply(tt.prefix, tt.in)
+			if tt.err != "" {
+				assert.ErrorContains(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.branch, res.Branch)
+			if tt.note != "" {
+				assert.Equal(t, tt.note, res.Note())
+			}
+		})
+	}
+}
+
+func TestCheckRefFormat(t *testing.T) {
+	names := []string{
+		"feature/login", "fix/a.b", "release/1.2", "PROJ-1",
+		"a..b", "-x", "x/", "x.", "x.lock", "a/.b", "a//b", "a@{b",
+		"a b", "a~b", "a^b", "a:b", "a?b", "a*b", "a[b", `a\b`, "a\tb",
+	}
+	for _, name := range names {
+		t.Run(name, func(t *testing.T) {
+			gitErr := exec.Command("git", "check-ref-format", "--branch", name).Run()
+			assert.Equal(t, gitErr == nil, CheckRefFormat(name) == nil, "must agree with git check-ref-format")
+		})
+	}
+}
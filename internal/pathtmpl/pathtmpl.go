@@ -0,0 +1,124 @@
+package pathtmpl
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Vars holds the values substituted into a name template
+type Vars struct {
+	Repo   string // ${REPO}: repository directory name
+	Branch string // ${BRANCH}: full branch name, e.g. feature/login
+	Name   string // ${NAME}: branch name without its prefix, e.g. login
+	Prefix string // ${PREFIX}: branch prefix, e.g. feature/
+	User   string // ${USER}: current user name
+}
+
+var unsafeChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// Sanitize turns a branch or ref name into a single, safe directory name.
+// Slashes become dashes ("feature/login" -> "feature-login"), anything outside
+// [A-Za-z0-9._-] is replaced and leading dots are dropped so that the result
+// can never be ".", ".." or a hidden directory.
+func Sanitize(s string) string {
+	s = strings.NewReplacer("/", "-", "\\", "-").Replace(s)
+	s = unsafeChars.ReplaceAllString(s, "-")
+	for strings.Contains(s, "--") {
+		s = strings.ReplaceAll(s, "--", "-")
+	}
+	return strings.Trim(s, ".-")
+}
+
+// SplitBranch splits a branch into its prefix and name. The configured
+// default prefix wins; otherwise everything up to the first slash is the
+// prefix ("bugfix/a" -> "bugfix/", "a").
+func SplitBranch(branch, defaultPrefix string) (prefix, name string) {
+	if defaultPrefix != "" && strings.HasPrefix(branch, defaultPrefix) && len(branch) > len(defaultPrefix) {
+		return defaultPrefix, strings.TrimPrefix(branch, defaultPrefix)
+	}
+	if i := strings.Index(branch, "/"); i > 0 && i < len(branch)-1 {
+		return branch[:i+1], branch[i+1:]
+	}
+	return "", branch
+}
+
+// CurrentUser returns the login name used for ${USER}
+func CurrentUser() string {
+	if u := os.Getenv("USER"); u != "" {
+		return u
+	}
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	return "user"
+}
+
+// Expand substitutes ${REPO}, ${BRANCH}, ${NAME}, ${PREFIX} and ${USER} in
+// template. Variable values are sanitized, so a branch can never introduce
+// extra path segments; slashes written in the template itself are kept.
+func Expand(template string, vars Vars) string {
+	r := strings.NewReplacer(
+		"${REPO}", Sanitize(vars.Repo),
+		"${BRANCH}", Sanitize(vars.Branch),
+		"${NAME}", Sanitize(vars.Name),
+		"${PREFIX}", Sanitize(vars.Prefix),
+		"${USER}", Sanitize(vars.User),
+	)
+	return r.Replace(template)
+}
+
+// ExpandHome replaces a leading ~ with the user's home directory
+func ExpandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, strings.TrimPrefix(path, "~"))
+}
+
+// WorktreePath builds the path of a worktree from the worktrees directory and
+// the name template. A relative worktreesDir is resolved against base.
+func WorktreePath(base, worktreesDir, template string, vars Vars) (string, error) {
+	if template == "" {
+		template = "${NAME}"
+	}
+	name := ExpandHome(Expand(template, vars))
+	if strings.Trim(name, "/.-") == "" {
+		return "", fmt.Errorf("name template %q expands to an empty name", template)
+	}
+	for _, part := range strings.Split(filepath.ToSlash(name), "/") {
+		if part == ".." {
+			return "", fmt.Errorf("name template %q must not contain '..'", template)
+		}
+	}
+	if filepath.IsAbs(name) {
+		return filepath.Clean(name), nil
+	}
+
+	dir := ExpandHome(worktreesDir)
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(base, dir)
+	}
+	return filepath.Join(dir, name), nil
+}
+
+// Unique returns path, or path with a numeric suffix ("-2", "-3", ...) when
+// taken reports that the path is already in use.
+func Unique(path string, taken func(string) bool) string {
+	if !taken(path) {
+		return path
+	}
+	for i := 2; ; i++ {
+		candidate := fmt.Sprintf("%s-%d", path, i)
+		if !taken(candidate) {
+			return candidate
+		}
+	}
+}
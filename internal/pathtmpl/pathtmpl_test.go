@@ -0,0 +1,118 @@
+package pathtmpl
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSanitize(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"login", "login"},
+		{"feature/login", "feature-login"},
+		{"feature//login", "feature-login"},
+		{"../../etc", "etc"},
+		{".hidden", "hidden"},
+		{"fix: crash on start", "fix-crash-on-start"},
+		{"release/1.2", "release-1.2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Sanitize(tt.input))
+		})
+	}
+}
+
+func TestSplitBranch(t *testing.T) {
+	tests := []struct {
+		branch, defaultPrefix string
+		prefix, name          string
+	}{
+		{"feature/a", "feature/", "feature/", "a"},
+		{"bugfix/a", "feature/", "bugfix/", "a"},
+		{"feature/auth/oidc", "feature/", "feature/", "auth/oidc"},
+		{"main", "feature/", "", "main"},
+		{"feature/", "feature/", "", "feature/"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.branch, func(t *testing.T) {
+			prefix, name := SplitBranch(tt.branch, tt.defaultPrefix)
+			assert.Equal(t, tt.prefix, prefix)
+			assert.Equal(t, tt.name, name)
+		})
+	}
+}
+
+func TestExpand(t *testing.T) {
+	vars := Vars{Repo: "shop", Branch: "feature/login", Name: "login", Prefix: "feature/", User: "ada"}
+
+	tests := []struct {
+		template string
+		expected string
+	}{
+		{"${REPO}-${BRANCH}", "shop-feature-login"},
+		{"${NAME}", "login"},
+		{"${PREFIX}/${NAME}", "feature/login"},
+		{"${USER}/${REPO}/${NAME}", "ada/shop/login"},
+		{"static", "static"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.template, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Expand(tt.template, vars))
+		})
+	}
+}
+
+func TestWorktreePath(t *testing.T) {
+	home, err := os.UserHomeDir()
+	assert.NoError(t, err)
+
+	feature := Vars{Repo: "shop", Branch: "feature/a", Name: "a", Prefix: "feature/"}
+	bugfix := Vars{Repo: "shop", Branch: "bugfix/a", Name: "a", Prefix: "bugfix/"}
+
+	tests := []struct {
+		name         string
+		worktreesDir string
+		template     string
+		vars         Vars
+		expected     string
+		wantErr      bool
+	}{
+		{"relative dir", "..", "${REPO}-${BRANCH}", feature, "/src/shop-feature-a", false},
+		{"branches with same name do not collide", "..", "${REPO}-${BRANCH}", bugfix, "/src/shop-bugfix-a", false},
+		{"nested dir", ".gitwo", "${NAME}", feature, "/src/shop/.gitwo/a", false},
+		{"home dir", "~/worktrees", "${REPO}/${NAME}", feature, filepath.Join(home, "worktrees", "shop", "a"), false},
+		{"absolute dir", "/wt", "${BRANCH}", feature, "/wt/feature-a", false},
+		{"empty template defaults to name", "..", "", feature, "/src/a", false},
+		{"template traversal rejected", "..", "../../${NAME}", feature, "", true},
+		{"empty expansion rejected", "..", "${USER}", feature, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, err := WorktreePath("/src/shop", tt.worktreesDir, tt.template, tt.vars)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, path)
+		})
+	}
+}
+
+func TestUnique(t *testing.T) {
+	taken := map[string]bool{"/wt/a": true, "/wt/a-2": true}
+	isTaken := func(p string) bool { return taken[p] }
+
+	assert.Equal(t, "/wt/b", Unique("/wt/b", isTaken))
+	assert.Equal(t, "/wt/a-3", Unique("/wt/a", isTaken))
+}
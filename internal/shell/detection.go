@@ -57,6 +57,12 @@ func GetShellProfilePath(shell string) string {
 	}
 }
 
+// Markers around the wrapper block that shell-install writes to the profile
+const (
+	WrapperBeginMarker = "# >>> gitwo shell wrapper >>>"
+	WrapperEndMarker   = "# <<< gitwo shell wrapper <<<"
+)
+
 // IsWrapperInstalled checks if the gitwo wrapper is installed in the shell profile
 func IsWrapperInstalled(shell string) bool {
 	profilePath := GetShellProfilePath(shell)
@@ -68,7 +74,23 @@ func IsWrapperInstalled(shell string) bool {
 	}
 
 	content := string(data)
-	return strings.Contains(content, "# >>> gitwo shell wrapper >>>")
+	return strings.Contains(content, WrapperBeginMarker)
+}
+
+// IsWrapperUpToDate checks whether the wrapper installed in the shell profile
+// is the one this gitwo generates. Older wrappers miss newer commands that
+// change directory.
+func IsWrapperUpToDate(shell string) bool {
+	data, err := os.ReadFile(GetShellProfilePath(shell))
+	if err != nil {
+		return false
+	}
+	_, block, ok := strings.Cut(string(data), WrapperBeginMarker)
+	if !ok {
+		return false
+	}
+	block, _, ok = strings.Cut(block, WrapperEndMarker)
+	return ok && strings.TrimSpace(block) == strings.TrimSpace(GenerateWrapper(shell))
 }
 
 // GetHomeDir returns the user's home directory
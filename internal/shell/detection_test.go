@@ -2,6 +2,7 @@ package shell
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -97,3 +98,24 @@ func TestGetShellProfilePath(t *testing.T) {
 		})
 	}
 }
+
+func TestIsWrapperUpToDate(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	profile := filepath.Join(home, ".bashrc")
+
+	write := func(wrapper string) {
+		content := "export PATH=$PATH\n" + WrapperBeginMarker + "\n" + wrapper + "\n" + WrapperEndMarker + "\n"
+		assert.NoError(t, os.WriteFile(profile, []byte(content), 0o644))
+	}
+
+	assert.False(t, IsWrapperUpToDate("bash"), "no profile")
+
+	write(GenerateWrapper("bash"))
+	assert.True(t, IsWrapperInstalled("bash"))
+	assert.True(t, IsWrapperUpToDate("bash"))
+
+	write(`gitwo() { command gitwo "$@"; }`)
+	assert.True(t, IsWrapperInstalled("bash"))
+	assert.False(t, IsWrapperUpToDate("bash"))
+}
@@ -1,74 +1,80 @@
 package shell
 
+import "strings"
+
+// CDCommands are the gitwo subcommands the wrapper runs with --shell. With
+// --shell gitwo writes its normal output to stderr and, when the shell should
+// change directory, prints "cd <path>" as the last line on stdout.
+var CDCommands = []string{"new", "remove", "rm", "switch", "sw", "move", "mv", "rename", "checkout-pr", "pr", "clone", "convert", "review"}
+
 // GenerateBashZshWrapper generates a bash/zsh wrapper function
 func GenerateBashZshWrapper() string {
-	return `# gitwo wrapper: auto-cd after "gitwo new ..."
-gitwo() {
-    if [[ "$1" == "new" ]]; then
-        export GITWO_WRAPPER=1
-        local line dest
-        line=$(command gitwo "$@" --shell | tail -n1) || return $?
-        dest=${line#cd }
-        [[ -n "$dest" ]] && builtin cd "$dest"
-    else
-        export GITWO_WRAPPER=1
-        command gitwo "$@"
-    fi
-}
-
+	return bashZshFunction("gitwo") + `
 # gitwo-dev wrapper: auto-cd after "gitwo-dev new ..."
 # Unalias gitwo-dev if it exists, then define as function
 unalias gitwo-dev 2>/dev/null || true
-gitwo-dev() {
-    if [[ "$1" == "new" ]]; then
-        export GITWO_WRAPPER=1
-        local line dest
-        line=$(command gitwo-dev "$@" --shell | tail -n1) || return $?
-        dest=${line#cd }
-        [[ -n "$dest" ]] && builtin cd "$dest"
-    else
-        export GITWO_WRAPPER=1
-        command gitwo-dev "$@"
-    fi
+` + bashZshFunction("gitwo-dev")
+}
+
+func bashZshFunction(name string) string {
+	return strings.NewReplacer("NAME", name, "COMMANDS", strings.Join(CDCommands, "|")).Replace(`# NAME wrapper: auto-cd after "NAME new ..." and when removing the current worktree
+NAME() {
+    export GITWO_WRAPPER=1
+    case "$1" in
+        COMMANDS)
+            local out line code
+            out=$(command NAME "$@" --shell)
+            code=$?
+            line=${out##*$'\n'}
+            if [[ "$line" == "cd "* ]]; then
+                builtin cd "${line#cd }" || return $?
+            fi
+            return $code
+            ;;
+        *)
+            command NAME "$@"
+            ;;
+    esac
 }
-`
+`)
 }
 
 // GenerateFishWrapper generates a fish shell wrapper function
 func GenerateFishWrapper() string {
-	return `# gitwo wrapper: auto-cd after "gitwo new ..."
-function gitwo --wraps gitwo --description "gitwo with auto-cd for new"
+	return strings.ReplaceAll(`# gitwo wrapper: auto-cd after "gitwo new ..." and when removing the current worktree
+function gitwo --wraps gitwo --description "gitwo with auto-cd"
     set -x GITWO_WRAPPER 1
-    if test (count $argv) -ge 1; and test $argv[1] = new
-        set line (command gitwo $argv --shell | tail -n 1)
-        set dest (string replace -r '^cd ' '' -- $line)
-        if test -n "$dest"
-            cd "$dest"
+    if test (count $argv) -ge 1; and contains -- $argv[1] COMMANDS
+        set -l out (command gitwo $argv --shell)
+        set -l code $status
+        set -l line $out[-1]
+        if string match -q 'cd *' -- "$line"
+            cd (string replace -r '^cd ' '' -- $line)
         end
+        return $code
     else
         command gitwo $argv
     end
 end
-`
+`, "COMMANDS", strings.Join(CDCommands, " "))
 }
 
 // GeneratePowerShellWrapper generates a PowerShell wrapper function
 func GeneratePowerShellWrapper() string {
-	return `# gitwo wrapper: auto-cd after "gitwo new ..."
+	return strings.ReplaceAll(`# gitwo wrapper: auto-cd after "gitwo new ..." and when removing the current worktree
 function gitwo {
     param([Parameter(ValueFromRemainingArguments=$true)] $Args)
     $env:GITWO_WRAPPER = "1"
-    if ($Args.Count -gt 0 -and $Args[0] -eq 'new') {
+    if ($Args.Count -gt 0 -and @(COMMANDS) -contains $Args[0]) {
         $line = & gitwo @Args --shell | Select-Object -Last 1
-        $dest = $line -replace '^cd\s+',''
-        if ($dest) {
-            Set-Location -Path $dest
+        if ($line -match '^cd\s+') {
+            Set-Location -Path ($line -replace '^cd\s+','')
         }
     } else {
         & gitwo @Args
     }
 }
-`
+`, "COMMANDS", "'"+strings.Join(CDCommands, "','")+"'")
 }
 
 // GenerateWrapper generates a wrapper for the specified shell
@@ -94,3 +94,13 @@ func TestWrapperSanity(t *testing.T) {
 		})
 	}
 }
+
+func TestWrappersHandleCDCommands(t *testing.T) {
+	for _, sh := range []string{"bash", "fish", "powershell"} {
+		wrapper := GenerateWrapper(sh)
+		for _, c := range CDCommands {
+			assert.Contains(t, wrapper, c, "%s wrapper should run %q with --shell", sh, c)
+		}
+		assert.Contains(t, wrapper, "--shell")
+	}
+}
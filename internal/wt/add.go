@@ -3,6 +3,7 @@ package wt
 import (
 	"bytes"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -24,6 +25,24 @@ func AddWithConfig(path, branch, startPoint string, config *Config) (*AddResult,
 }
 
 func AddWithConfigSilent(path, branch, startPoint string, config *Config, silent bool) (*AddResult, error) {
+	if branch == "" && path != "" {
+		return nil, fmt.Errorf("branch cannot be empty")
+	}
+	return addWorktree(path, branch, startPoint, config, silent)
+}
+
+// AddDetached creates a worktree with a detached HEAD at ref (a tag, commit
+// or any other ref), e.g. to inspect a release
+func AddDetached(path, ref string, config *Config, silent bool) (*AddResult, error) {
+	if gitSilent("rev-parse", "--verify", "--quiet", ref+"^{commit}") != nil {
+		return nil, fmt.Errorf("%q does not name a commit", ref)
+	}
+	return addWorktree(path, "", ref, config, silent)
+}
+
+// addWorktree creates the worktree at path for branch (reset to startPoint),
+// or detached at startPoint when branch is empty
+func addWorktree(path, branch, startPoint string, config *Config, silent bool) (*AddResult, error) {
 	// Create progress display
 	var progress *ProgressDisplay
 	if silent {
@@ -59,22 +78,18 @@ func AddWithConfigSilent(path, branch, startPoint string, config *Config, silent
 	}
 
 	// Print header
+	detached := branch == ""
 	if !silent {
-		PrintHeader(repoRootPath, repoBranch, repoHead, startPoint, branch, path)
-
-		// Show branch normalization if applicable
-		if strings.HasPrefix(branch, "feature/") {
-			originalName := strings.TrimPrefix(branch, "feature/")
-			fmt.Printf("note: normalized \"%s\" → \"%s\" (policy: feature/*)\n\n", originalName, branch)
+		label := branch
+		if detached {
+			label = "(detached at " + startPoint + ")"
 		}
+		PrintHeader(repoRootPath, repoBranch, repoHead, startPoint, label, label, path)
 	}
 	// Validate inputs
 	if path == "" {
 		return nil, fmt.Errorf("path cannot be empty")
 	}
-	if branch == "" {
-		return nil, fmt.Errorf("branch cannot be empty")
-	}
 	if startPoint == "" {
 		return nil, fmt.Errorf("start point cannot be empty")
 	}
@@ -119,7 +134,11 @@ func AddWithConfigSilent(path, branch, startPoint string, config *Config, silent
 	}
 
 	// Step 3: Create branch
-	branchStep := progress.AddStep("Creating branch")
+	branchMsg := "Creating branch"
+	if detached {
+		branchMsg = "Preparing detached HEAD"
+	}
+	branchStep := progress.AddStep(branchMsg)
 
 	// Check if target path already exists
 	if _, err := os.Stat(path); err == nil {
@@ -134,12 +153,20 @@ func AddWithConfigSilent(path, branch, startPoint string, config *Config, silent
 		progress.RenderStep(branchStep)
 		return nil, fmt.Errorf("mkdir: %w", err)
 	}
-	progress.UpdateStep(branchStep, "OK", branch)
+	if detached {
+		progress.UpdateStep(branchStep, "OK", startPoint)
+	} else {
+		progress.UpdateStep(branchStep, "OK", branch)
+	}
 	progress.RenderStep(branchStep)
 
 	// Step 4: Create worktree
 	worktreeStep := progress.AddStep("Creating worktree")
-	if err := git("worktree", "add", "-B", branch, path, startPoint); err != nil {
+	wtArgs := []string{"worktree", "add", "-B", branch, path, startPoint}
+	if detached {
+		wtArgs = []string{"worktree", "add", "--detach", path, startPoint}
+	}
+	if err := git(wtArgs...); err != nil {
 		progress.UpdateStep(worktreeStep, "ERROR", err.Error())
 		progress.RenderStep(worktreeStep)
 		return nil, err
@@ -149,7 +176,9 @@ func AddWithConfigSilent(path, branch, startPoint string, config *Config, silent
 
 	// Step 5: Set upstream
 	upstreamStep := progress.AddStep("Setting upstream")
-	if refIsRemote(startPoint) {
+	if detached {
+		progress.UpdateStep(upstreamStep, "OK", "detached, no upstream")
+	} else if refIsRemote(startPoint) {
 		if err := run("bash", "-lc", fmt.Sprintf("cd %q && git branch --set-upstream-to %s %s", path, startPoint, branch)); err != nil {
 			progress.UpdateStep(upstreamStep, "ERROR", err.Error())
 		} else {
@@ -160,6 +189,17 @@ func AddWithConfigSilent(path, branch, startPoint string, config *Config, silent
 	}
 	progress.RenderStep(upstreamStep)
 
+	// Step 6: Initialize submodules, which worktree add leaves empty
+	if HasSubmodules(path) {
+		subStep := progress.AddStep("Updating submodules")
+		if updated, err := UpdateSubmodules(path, io.Discard); err != nil {
+			progress.UpdateStep(subStep, "ERROR", err.Error())
+		} else {
+			progress.UpdateStep(subStep, "OK", SubmoduleUpdateSummary(updated))
+		}
+		progress.RenderStep(subStep)
+	}
+
 	// Get the actual branch name that was checked out
 	actualBranch := branch
 	if out, err := gitOut("-C", path, "branch", "--show-current"); err == nil {
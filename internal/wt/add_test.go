@@ -175,3 +175,19 @@ func TestAdd_Validation(t *testing.T) {
 		})
 	}
 }
+
+func TestAddDetached(t *testing.T) {
+	repo := newTestRepo(t)
+	runGit(t, repo, "tag", "-a", "-m", "release", "v1.0")
+	commitFile(t, repo, "later.txt", "later\n")
+	path := filepath.Join(filepath.Dir(repo), "v1.0")
+
+	res, err := AddDetached(path, "v1.0", nil, true)
+	require.NoError(t, err)
+	assert.Empty(t, res.Branch)
+	assert.Equal(t, runGit(t, repo, "rev-parse", "v1.0^{commit}"), res.Head+"\n")
+	assert.NoFileExists(t, filepath.Join(path, "later.txt"))
+
+	_, err = AddDetached(filepath.Join(filepath.Dir(repo), "nope"), "no-such-tag", nil, true)
+	assert.ErrorContains(t, err, "does not name a commit")
+}
@@ -0,0 +1,236 @@
+package wt
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// BisectVerdict is the outcome of testing one commit
+type BisectVerdict int
+
+const (
+	BisectGood BisectVerdict = iota
+	BisectBad
+	BisectSkip // the commit cannot be tested, like exit code 125 for git bisect run
+)
+
+func (v BisectVerdict) String() string {
+	switch v {
+	case BisectGood:
+		return "good"
+	case BisectBad:
+		return "bad"
+	default:
+		return "skip"
+	}
+}
+
+// bisectTTL is how long bisect worktrees left behind by a crash survive
+// before gc removes them
+const bisectTTL = 24 * time.Hour
+
+// BisectRange lists the commits to search for the first bad one, oldest
+// first: the first-parent history of bad that none of the good revisions
+// contain. The last element is bad itself.
+func BisectRange(bad string, good []string) ([]string, error) {
+	if len(good) == 0 {
+		return nil, fmt.Errorf("at least one good revision is required")
+	}
+	badSHA, err := resolveCommit(bad)
+	if err != nil {
+		return nil, err
+	}
+	args := []string{"rev-list", "--reverse", "--first-parent", badSHA}
+	for _, g := range good {
+		sha, err := resolveCommit(g)
+		if err != nil {
+			return nil, err
+		}
+		if gitSilent("merge-base", "--is-ancestor", sha, badSHA) != nil {
+			return nil, fmt.Errorf("good revision %s is not an ancestor of %s", g, bad)
+		}
+		args = append(args, "^"+sha)
+	}
+	out, err := gitOut(args...)
+	if err != nil {
+		return nil, fmt.Errorf("git rev-list failed: %w", err)
+	}
+	commits := strings.Fields(string(out))
+	if len(commits) == 0 {
+		return nil, fmt.Errorf("no commits between the good revisions and %s", bad)
+	}
+	return commits, nil
+}
+
+func resolveCommit(ref string) (string, error) {
+	out, err := gitOut("rev-parse", "--verify", "--quiet", ref+"^{commit}")
+	if err != nil {
+		return "", fmt.Errorf("unknown revision %q", ref)
+	}
+	return string(bytesTrimNL(out)), nil
+}
+
+// BisectSearch narrows a BisectRange down to its first bad commit, testing
+// several commits per round: n commits split the remaining range into n+1
+// parts, so each round shrinks it about n+1 times instead of halving it.
+type BisectSearch struct {
+	Commits  []string // oldest first; the last one is known to be bad
+	verdicts map[int]BisectVerdict
+	index    map[string]int
+}
+
+// NewBisectSearch starts a search over commits from BisectRange
+func NewBisectSearch(commits []string) *BisectSearch {
+	s := &BisectSearch{Commits: commits, verdicts: map[int]BisectVerdict{}, index: map[string]int{}}
+	for i, c := range commits {
+		s.index[c] = i
+	}
+	s.verdicts[len(commits)-1] = BisectBad
+	return s
+}
+
+// bounds returns the index of the oldest bad commit and of the newest good
+// commit before it (-1 for the good revisions themselves). Good verdicts
+// above the oldest bad commit are ignored.
+func (s *BisectSearch) bounds() (good, bad int) {
+	good, bad = -1, len(s.Commits)-1
+	for i, v := range s.verdicts {
+		if v == BisectBad && i < bad {
+			bad = i
+		}
+	}
+	for i, v := range s.verdicts {
+		if v == BisectGood && i > good && i < bad {
+			good = i
+		}
+	}
+	return good, bad
+}
+
+// open lists the untested commits between the bounds
+func (s *BisectSearch) open() []int {
+	good, bad := s.bounds()
+	var idx []int
+	for i := good + 1; i < bad; i++ {
+		if _, tested := s.verdicts[i]; !tested {
+			idx = append(idx, i)
+		}
+	}
+	return idx
+}
+
+// Remaining is the number of commits that may still need testing
+func (s *BisectSearch) Remaining() int {
+	return len(s.open())
+}
+
+// Done reports whether no commit is left to test
+func (s *BisectSearch) Done() bool {
+	return s.Remaining() == 0
+}
+
+// Next picks up to n commits for the next round, evenly spaced over the
+// untested part of the range
+func (s *BisectSearch) Next(n int) []string {
+	open := s.open()
+	if n < 1 {
+		n = 1
+	}
+	var next []string
+	if len(open) <= n {
+		for _, i := range open {
+			next = append(next, s.Commits[i])
+		}
+		return next
+	}
+	for k := 1; k <= n; k++ {
+		next = append(next, s.Commits[open[k*len(open)/(n+1)]])
+	}
+	return next
+}
+
+// Mark records the verdict for a commit returned by Next
+func (s *BisectSearch) Mark(commit string, v BisectVerdict) {
+	if i, ok := s.index[commit]; ok {
+		s.verdicts[i] = v
+	}
+}
+
+// Result returns the first bad commit once the search is done. When skipped
+// commits keep it from being pinned down, first is "" and suspects lists
+// the commits that may be the first bad one, oldest first.
+func (s *BisectSearch) Result() (first string, suspects []string) {
+	good, bad := s.bounds()
+	suspects = s.Commits[good+1 : bad+1]
+	if len(suspects) == 1 {
+		return suspects[0], suspects
+	}
+	return "", suspects
+}
+
+// Tested lists the commits that have a verdict, oldest first, with the
+// verdicts
+func (s *BisectSearch) Tested() ([]string, []BisectVerdict) {
+	idx := make([]int, 0, len(s.verdicts))
+	for i := range s.verdicts {
+		idx = append(idx, i)
+	}
+	sort.Ints(idx)
+	commits := make([]string, len(idx))
+	verdicts := make([]BisectVerdict, len(idx))
+	for k, i := range idx {
+		commits[k], verdicts[k] = s.Commits[i], s.verdicts[i]
+	}
+	return commits, verdicts
+}
+
+// AddBisectWorktree creates a temporary detached worktree at head for
+// testing commits. Like review worktrees it lives under EphemeralDir, so gc
+// cleans it up if bisect cannot.
+func AddBisectWorktree(head string) (string, error) {
+	root, err := MainRoot()
+	if err != nil {
+		return "", err
+	}
+	return addEphemeral(root, "bisect", &EphemeralMeta{Ref: "bisect", Head: head, Expires: time.Now().Add(bisectTTL)})
+}
+
+// RemoveBisectWorktree removes a worktree from AddBisectWorktree along with
+// whatever the tests left in it
+func RemoveBisectWorktree(path string) error {
+	root, err := MainRoot()
+	if err != nil {
+		return err
+	}
+	if out, err := gitIn(root, "worktree", "remove", "--force", path); err != nil {
+		return fmt.Errorf("git worktree remove failed: %s", lastLine(out))
+	}
+	return nil
+}
+
+// CheckoutForBisect moves a bisect worktree to commit, discarding changes
+// the previous test run made to tracked files. Untracked files such as build
+// caches are left alone.
+func CheckoutForBisect(path, commit string) error {
+	if out, err := gitIn(path, "checkout", "--quiet", "--force", "--detach", commit); err != nil {
+		return fmt.Errorf("git checkout %s failed: %s", ShortSHA(commit), lastLine(out))
+	}
+	if HasSubmodules(path) {
+		if _, err := UpdateSubmodules(path, io.Discard); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CommitSubject returns the first line of a commit's message
+func CommitSubject(commit string) string {
+	out, err := gitOut("log", "-1", "--format=%s", commit, "--")
+	if err != nil {
+		return ""
+	}
+	return string(bytesTrimNL(out))
+}
@@ -0,0 +1,111 @@
+package wt
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBisectSearch(t *testing.T) {
+	commits := make([]string, 40)
+	for i := range commits {
+		commits[i] = fmt.Sprintf("c%02d", i)
+	}
+	verdict := func(c string, firstBad int, skip map[string]bool) BisectVerdict {
+		var i int
+		fmt.Sscanf(c, "c%d", &i)
+		switch {
+		case skip[c]:
+			return BisectSkip
+		case i >= firstBad:
+			return BisectBad
+		}
+		return BisectGood
+	}
+	search := func(firstBad, jobs int, skip map[string]bool) (*BisectSearch, int) {
+		s := NewBisectSearch(commits)
+		rounds := 0
+		for !s.Done() {
+			batch := s.Next(jobs)
+			require.NotEmpty(t, batch)
+			require.LessOrEqual(t, len(batch), jobs)
+			for _, c := range batch {
+				s.Mark(c, verdict(c, firstBad, skip))
+			}
+			rounds++
+		}
+		return s, rounds
+	}
+
+	for _, firstBad := range []int{0, 1, 17, 38, 39} {
+		for _, jobs := range []int{1, 3, 8} {
+			s, rounds := search(firstBad, jobs, nil)
+			first, _ := s.Result()
+			assert.Equal(t, commits[firstBad], first, "first bad %d, %d jobs", firstBad, jobs)
+			if jobs == 8 {
+				// 39 untested commits split into 9 parts of at most 5
+				assert.LessOrEqual(t, rounds, 2)
+			}
+		}
+	}
+
+	t.Run("skipped commits leave suspects", func(t *testing.T) {
+		s, _ := search(20, 4, map[string]bool{"c19": true, "c20": true})
+		first, suspects := s.Result()
+		assert.Empty(t, first)
+		assert.Equal(t, []string{"c19", "c20", "c21"}, suspects)
+	})
+
+	t.Run("a good verdict after a bad one is ignored", func(t *testing.T) {
+		s := NewBisectSearch(commits[:5])
+		s.Mark("c01", BisectBad)
+		s.Mark("c03", BisectGood)
+		s.Mark("c00", BisectGood)
+		assert.True(t, s.Done())
+		first, _ := s.Result()
+		assert.Equal(t, "c01", first)
+	})
+}
+
+func TestBisectRange(t *testing.T) {
+	repo := newTestRepo(t)
+	runGit(t, repo, "tag", "good")
+	for i := 0; i < 3; i++ {
+		commitFile(t, repo, fmt.Sprintf("f%d.txt", i), "x\n")
+	}
+
+	commits, err := BisectRange("HEAD", []string{"good"})
+	require.NoError(t, err)
+	require.Len(t, commits, 3)
+	assert.Equal(t, strings.TrimSpace(runGit(t, repo, "rev-parse", "HEAD")), commits[2])
+	assert.Equal(t, "update f0.txt", CommitSubject(commits[0]))
+
+	_, err = BisectRange("good", []string{"HEAD"})
+	assert.ErrorContains(t, err, "not an ancestor")
+	_, err = BisectRange("HEAD", []string{"HEAD"})
+	assert.ErrorContains(t, err, "no commits")
+	_, err = BisectRange("HEAD", []string{"nope"})
+	assert.ErrorContains(t, err, "unknown revision")
+}
+
+func TestBisectWorktree(t *testing.T) {
+	repo := newTestRepo(t)
+	first := strings.TrimSpace(runGit(t, repo, "rev-parse", "HEAD"))
+	commitFile(t, repo, "later.txt", "later\n")
+
+	path, err := AddBisectWorktree(strings.TrimSpace(runGit(t, repo, "rev-parse", "HEAD")))
+	require.NoError(t, err)
+	assert.FileExists(t, filepath.Join(path, "later.txt"))
+
+	// Changes left by a test run do not get in the way
+	writeFile(t, path, "later.txt", "modified\n")
+	require.NoError(t, CheckoutForBisect(path, first))
+	assert.NoFileExists(t, filepath.Join(path, "later.txt"))
+
+	require.NoError(t, RemoveBisectWorktree(path))
+	assert.NoDirExists(t, path)
+}
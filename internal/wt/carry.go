@@ -0,0 +1,122 @@
+package wt
+
+import (
+	"fmt"
+	"strings"
+)
+
+// StashConflictError is returned when a stash did not apply cleanly. The
+// stash itself is left untouched.
+type StashConflictError struct {
+	Path   string // worktree the stash was applied in
+	Stash  string // e.g. stash@{0}
+	SHA    string
+	Output string // git's explanation
+}
+
+func (e *StashConflictError) Error() string {
+	msg := fmt.Sprintf("the changes did not apply cleanly in %s; they are still in %s (%s)", e.Path, e.Stash, ShortSHA(e.SHA))
+	if e.Output != "" {
+		msg += "\n" + e.Output
+	}
+	return msg + fmt.Sprintf("\nresolve the conflicts there, or discard them and retry with: git stash apply %s", e.Stash)
+}
+
+// CarryOut stashes the uncommitted changes of the worktree at dir so they
+// can be applied in another worktree, leaving dir clean. Untracked files are
+// included when untracked is true. It returns the stash commit, or "" when
+// there was nothing to carry.
+func CarryOut(dir string, untracked bool, message string) (string, error) {
+	st := GetStatus(WorktreeItem{Path: dir}, "")
+	if st.Err != nil {
+		return "", st.Err
+	}
+	if st.Staged+st.Unstaged+st.Conflicted == 0 && (!untracked || st.Untracked == 0) {
+		return "", nil
+	}
+	if st.Conflicted > 0 {
+		return "", fmt.Errorf("%s has unresolved conflicts; resolve them before carrying the changes", dir)
+	}
+
+	args := []string{"stash", "push", "--quiet", "--message", message}
+	if untracked {
+		args = append(args, "--include-untracked")
+	}
+	if out, err := gitIn(dir, args...); err != nil {
+		return "", fmt.Errorf("git stash failed: %s", lastLine(out))
+	}
+	out, err := gitIn(dir, "rev-parse", "--verify", "refs/stash")
+	if err != nil {
+		return "", fmt.Errorf("cannot find the new stash: %s", lastLine(out))
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// ResolveStash returns the commit of a stash entry such as stash@{1}
+func ResolveStash(dir, ref string) (string, error) {
+	out, err := gitIn(dir, "rev-parse", "--verify", "--quiet", ref+"^{commit}")
+	if err != nil {
+		return "", fmt.Errorf("no stash entry %s", ref)
+	}
+	sha := strings.TrimSpace(out)
+	if StashRef(dir, sha) == "" {
+		return "", fmt.Errorf("%s is not a stash entry (see git stash list)", ref)
+	}
+	return sha, nil
+}
+
+// StashRef returns the stash@{n} name of the stash commit sha, or "" when it
+// is not in the stash list
+func StashRef(dir, sha string) string {
+	// `git stash list` needs a work tree; the reflog works anywhere
+	out, err := gitIn(dir, "log", "--walk-reflogs", "--format=%H", "refs/stash", "--")
+	if err != nil {
+		return ""
+	}
+	for i, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		if line == sha {
+			return fmt.Sprintf("stash@{%d}", i)
+		}
+	}
+	return ""
+}
+
+// ApplyStash applies the stash commit sha in the worktree at path, staged
+// changes staged again where possible. It reports whether the index could be
+// restored. On conflicts a *StashConflictError is returned; the stash is never
+// dropped here.
+func ApplyStash(path, sha string) (bool, error) {
+	out, err := gitIn(path, "stash", "apply", "--quiet", "--index", sha)
+	if err == nil {
+		return true, nil
+	}
+	// --index gives up without touching anything when the staged changes do
+	// not apply; the work tree changes may still merge
+	if st := GetStatus(WorktreeItem{Path: path}, ""); st.Err == nil && !st.Dirty() {
+		out, err = gitIn(path, "stash", "apply", "--quiet", sha)
+		if err == nil {
+			return false, nil
+		}
+	}
+	return false, &StashConflictError{Path: path, Stash: StashRef(path, sha), SHA: sha, Output: strings.TrimSpace(out)}
+}
+
+// DropStash removes the stash commit sha from the stash list
+func DropStash(dir, sha string) error {
+	ref := StashRef(dir, sha)
+	if ref == "" {
+		return nil
+	}
+	if out, err := gitIn(dir, "stash", "drop", "--quiet", ref); err != nil {
+		return fmt.Errorf("git stash drop %s failed: %s", ref, lastLine(out))
+	}
+	return nil
+}
+
+// ShortSHA abbreviates a commit id for messages
+func ShortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
@@ -0,0 +1,82 @@
+package wt
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCarryOut_AppliesInNewWorktree(t *testing.T) {
+	repo := newTestRepo(t)
+	writeFile(t, repo, "README.md", "# changed\n")
+	writeFile(t, repo, "staged.txt", "staged\n")
+	runGit(t, repo, "add", "staged.txt")
+	writeFile(t, repo, "notes.txt", "untracked\n")
+	statusBefore := runGit(t, repo, "status", "--porcelain")
+
+	sha, err := CarryOut(repo, true, "gitwo: carry to feature/x")
+	require.NoError(t, err)
+	require.NotEmpty(t, sha)
+	assert.Empty(t, runGit(t, repo, "status", "--porcelain"))
+	assert.Equal(t, "stash@{0}", StashRef(repo, sha))
+
+	path := filepath.Join(filepath.Dir(repo), "feature")
+	runGit(t, repo, "worktree", "add", "-q", "-b", "feature/x", path)
+	restaged, err := ApplyStash(path, sha)
+	require.NoError(t, err)
+	assert.True(t, restaged)
+	assert.Equal(t, statusBefore, runGit(t, path, "status", "--porcelain"))
+
+	require.NoError(t, DropStash(path, sha))
+	assert.Empty(t, StashRef(repo, sha))
+}
+
+func TestCarryOut_UntrackedOnlyWhenAsked(t *testing.T) {
+	repo := newTestRepo(t)
+	writeFile(t, repo, "notes.txt", "untracked\n")
+
+	sha, err := CarryOut(repo, false, "carry")
+	require.NoError(t, err)
+	assert.Empty(t, sha, "untracked files alone are nothing to carry without untracked")
+	assert.FileExists(t, filepath.Join(repo, "notes.txt"))
+}
+
+func TestApplyStash_ConflictKeepsStash(t *testing.T) {
+	repo := newTestRepo(t)
+	runGit(t, repo, "branch", "other")
+	writeFile(t, repo, "README.md", "# mine\n")
+	sha, err := CarryOut(repo, false, "carry")
+	require.NoError(t, err)
+
+	path := filepath.Join(filepath.Dir(repo), "other")
+	runGit(t, repo, "worktree", "add", "-q", path, "other")
+	commitFile(t, path, "README.md", "# theirs\n")
+
+	_, err = ApplyStash(path, sha)
+	var conflict *StashConflictError
+	require.ErrorAs(t, err, &conflict)
+	assert.Equal(t, "stash@{0}", conflict.Stash)
+	assert.Equal(t, sha, strings.TrimSpace(runGit(t, repo, "rev-parse", "stash@{0}")))
+	assert.Contains(t, err.Error(), "git stash apply stash@{0}")
+}
+
+func TestResolveStash(t *testing.T) {
+	repo := newTestRepo(t)
+	writeFile(t, repo, "README.md", "# first\n")
+	runGit(t, repo, "stash", "-q")
+	writeFile(t, repo, "README.md", "# second\n")
+	runGit(t, repo, "stash", "-q")
+
+	sha, err := ResolveStash(repo, "stash@{1}")
+	require.NoError(t, err)
+	assert.Equal(t, "stash@{1}", StashRef(repo, sha))
+
+	_, err = ResolveStash(repo, "stash@{5}")
+	assert.Error(t, err)
+	_, err = ResolveStash(repo, "main")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not a stash entry")
+}
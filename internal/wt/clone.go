@@ -0,0 +1,102 @@
+package wt
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/gitwohq/gitwo/internal/pathtmpl"
+)
+
+// bareDir is where Clone puts the bare repository inside the layout root
+const bareDir = ".bare"
+
+// CloneResult describes a freshly cloned bare layout
+type CloneResult struct {
+	Root          string // layout root holding .bare, .git and the worktrees
+	DefaultBranch string
+	Worktree      string // path of the worktree for the default branch
+}
+
+// CloneDir derives the directory name for a clone from its URL, like git does
+// ("https://host/team/shop.git" -> "shop")
+func CloneDir(url string) string {
+	name := strings.TrimRight(url, "/")
+	if i := strings.LastIndexAny(name, "/:"); i >= 0 {
+		name = name[i+1:]
+	}
+	return strings.TrimSuffix(name, ".git")
+}
+
+// Clone creates the bare layout in dir:
+//
+//	dir/.bare       bare clone of url
+//	dir/.git        file pointing at .bare
+//	dir/<branch>    worktree for the default branch
+//
+// Unlike a plain bare clone, the remote gets a normal fetch refspec so
+// remote-tracking branches exist, and only the default branch is kept as a
+// local branch (tracking its remote). git's progress goes to progress.
+func Clone(url, dir string, progress io.Writer) (*CloneResult, error) {
+	root, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	if entries, err := os.ReadDir(root); err == nil && len(entries) > 0 {
+		return nil, fmt.Errorf("%s already exists and is not empty", root)
+	}
+	bare := filepath.Join(root, bareDir)
+
+	gitTo := func(args ...string) error {
+		cmd := exec.Command("git", args...)
+		cmd.Stdout = progress
+		cmd.Stderr = progress
+		return cmd.Run()
+	}
+	if err := gitTo("clone", "--bare", url, bare); err != nil {
+		return nil, fmt.Errorf("git clone failed: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, ".git"), []byte("gitdir: ./"+bareDir+"\n"), 0o644); err != nil {
+		return nil, err
+	}
+
+	// A bare clone maps remote branches onto local ones and has no fetch
+	// refspec; switch to the usual remote-tracking setup
+	if out, err := gitIn(bare, "config", "remote.origin.fetch", "+refs/heads/*:refs/remotes/origin/*"); err != nil {
+		return nil, fmt.Errorf("failed to set fetch refspec: %s", lastLine(out))
+	}
+	if err := gitTo("-C", bare, "fetch", "--quiet", "origin"); err != nil {
+		return nil, fmt.Errorf("git fetch failed: %w", err)
+	}
+	_, _ = gitIn(bare, "remote", "set-head", "origin", "--auto")
+
+	res := &CloneResult{Root: root}
+	out, err := gitIn(bare, "symbolic-ref", "--short", "HEAD")
+	if err != nil {
+		return nil, fmt.Errorf("cannot determine the default branch: %s", lastLine(out))
+	}
+	res.DefaultBranch = strings.TrimSpace(out)
+
+	// Drop the local copies of other branches; they live on as origin/*
+	branches, err := gitIn(bare, "for-each-ref", "--format=%(refname:short)", "refs/heads/")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list branches: %s", lastLine(branches))
+	}
+	for _, b := range strings.Fields(branches) {
+		if b != res.DefaultBranch {
+			_, _ = gitIn(bare, "branch", "-D", b)
+		}
+	}
+	if gitSilent("-C", bare, "rev-parse", "--verify", "--quiet", "refs/remotes/origin/"+res.DefaultBranch) == nil {
+		_, _ = gitIn(bare, "branch", "--set-upstream-to=origin/"+res.DefaultBranch, res.DefaultBranch)
+	}
+
+	res.Worktree = filepath.Join(root, pathtmpl.Sanitize(res.DefaultBranch))
+	if out, err := gitIn(root, "worktree", "add", "--quiet", res.Worktree, res.DefaultBranch); err != nil {
+		return nil, fmt.Errorf("failed to create the %s worktree: %s", res.DefaultBranch, lastLine(out))
+	}
+	return res, nil
+}
@@ -0,0 +1,66 @@
+package wt
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCloneDir(t *testing.T) {
+	tests := map[string]string{
+		"https://github.com/acme/shop.git": "shop",
+		"git@github.com:acme/shop.git":     "shop",
+		"file:///srv/git/shop/":            "shop",
+		"../shop":                          "shop",
+	}
+	for url, want := range tests {
+		assert.Equal(t, want, CloneDir(url), url)
+	}
+}
+
+func TestClone_BareLayout(t *testing.T) {
+	src := newTestRepo(t)
+	runGit(t, src, "branch", "feature/other")
+	parent := filepath.Dir(src)
+	t.Chdir(parent)
+
+	res, err := Clone("file://"+src, "shop", io.Discard)
+	require.NoError(t, err)
+	root := filepath.Join(parent, "shop")
+	assert.Equal(t, root, res.Root)
+	assert.Equal(t, "main", res.DefaultBranch)
+	assert.Equal(t, filepath.Join(root, "main"), res.Worktree)
+
+	gitFile, err := os.ReadFile(filepath.Join(root, ".git"))
+	require.NoError(t, err)
+	assert.Equal(t, "gitdir: ./.bare\n", string(gitFile))
+	assert.FileExists(t, filepath.Join(res.Worktree, "README.md"))
+
+	// Remote branches are remote-tracking refs; only the default branch is local
+	assert.Equal(t, "main", strings.TrimSpace(runGit(t, root, "for-each-ref", "--format=%(refname:short)", "refs/heads/")))
+	runGit(t, root, "rev-parse", "--verify", "origin/feature/other")
+	assert.Equal(t, "origin/main", strings.TrimSpace(runGit(t, root, "rev-parse", "--abbrev-ref", "main@{upstream}")))
+
+	for _, dir := range []string{root, filepath.Join(root, ".bare"), res.Worktree} {
+		t.Run("works from "+filepath.Base(dir), func(t *testing.T) {
+			t.Chdir(dir)
+			mainRoot, err := MainRoot()
+			require.NoError(t, err)
+			assert.Equal(t, root, mainRoot)
+
+			items, err := List()
+			require.NoError(t, err)
+			require.Len(t, items, 2)
+			assert.True(t, items[0].Bare)
+			assert.Equal(t, "main", items[1].Branch)
+		})
+	}
+
+	_, err = Clone("file://"+src, "shop", io.Discard)
+	assert.ErrorContains(t, err, "not empty")
+}
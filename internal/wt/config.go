@@ -77,3 +77,13 @@ func SaveConfig(config *Config) error {
 
 	return nil
 }
+
+// ConfigPath returns the file LoadConfig and SaveConfig use: the
+// .gitwo/config.yml of the current worktree
+func ConfigPath() (string, error) {
+	root, err := repoRoot()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(root, ".gitwo", "config.yml"), nil
+}
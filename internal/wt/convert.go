@@ -0,0 +1,326 @@
+package wt
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gitwohq/gitwo/internal/pathtmpl"
+)
+
+// Scratch directories Convert uses inside the root while it works
+const (
+	convertStaging = ".gitwo-convert"
+	convertScratch = ".gitwo-convert-tmp"
+)
+
+// ConvertPlan describes how Convert turns a normal clone into the bare layout
+// (see Clone). Everything in the .git directory (branches, stashes, config,
+// hooks, linked worktrees) moves to .bare unchanged; the files of the current
+// checkout move into a worktree named after its branch, index included, so
+// staged, unstaged and untracked changes survive.
+type ConvertPlan struct {
+	Root     string   // root of the main worktree, which becomes the layout root
+	Branch   string   // branch checked out in the main worktree
+	Worktree string   // where the current checkout ends up
+	Entries  []string // top-level entries moved into Worktree
+	Linked   []string // linked worktrees whose links are repaired afterwards
+	KeepDirs []string // entries that stay in the root, e.g. an untracked .gitwo/
+
+	// failAt makes the step with this index fail; tests use it to exercise
+	// the rollback
+	failAt int
+}
+
+// PlanConvert checks that the current repository can be converted and works
+// out what Convert will do
+func PlanConvert() (*ConvertPlan, error) {
+	root, err := MainRoot()
+	if err != nil {
+		return nil, err
+	}
+	gitPath := filepath.Join(root, ".git")
+	fi, err := os.Stat(gitPath)
+	if err != nil {
+		return nil, fmt.Errorf("%s is not the root of a git clone", root)
+	}
+	if !fi.IsDir() {
+		if _, ok := bareLayoutRoot(filepath.Join(root, bareDir)); ok {
+			return nil, fmt.Errorf("%s already uses the bare layout", root)
+		}
+		return nil, fmt.Errorf("%s has a .git file instead of a directory; gitwo can only convert normal clones", root)
+	}
+	if _, err := os.Lstat(filepath.Join(root, bareDir)); err == nil {
+		return nil, fmt.Errorf("%s already exists", filepath.Join(root, bareDir))
+	}
+	for _, scratch := range []string{convertStaging, convertScratch} {
+		if _, err := os.Lstat(filepath.Join(root, scratch)); err == nil {
+			return nil, fmt.Errorf("%s exists, probably left over from an interrupted conversion; remove it first", filepath.Join(root, scratch))
+		}
+	}
+	if out, err := gitIn(root, "config", "--get", "core.worktree"); err == nil {
+		return nil, fmt.Errorf("core.worktree is set (%s); gitwo cannot convert such a repository", strings.TrimSpace(out))
+	}
+	if op := operationInProgress(root); op != "" {
+		return nil, fmt.Errorf("a %s is in progress in %s; finish or abort it first", op, root)
+	}
+	if subs := populatedSubmodules(root); len(subs) > 0 {
+		return nil, fmt.Errorf(`%s has initialized submodules (%s)
+their repositories live in .git/modules and are linked by relative paths that
+would break. Deinitialize them first and update them again afterwards:
+  git submodule deinit --all
+  gitwo convert
+  git -C <worktree> submodule update --init`, root, strings.Join(subs, ", "))
+	}
+
+	plan := &ConvertPlan{Root: root, failAt: -1}
+	out, err := gitIn(root, "symbolic-ref", "--quiet", "--short", "HEAD")
+	if err != nil {
+		return nil, fmt.Errorf("HEAD is detached; check out a branch before converting")
+	}
+	plan.Branch = strings.TrimSpace(out)
+	if gitSilent("-C", root, "rev-parse", "--verify", "--quiet", "HEAD") != nil {
+		return nil, fmt.Errorf("%s has no commits yet", plan.Branch)
+	}
+	plan.Worktree = filepath.Join(root, pathtmpl.Sanitize(plan.Branch))
+
+	// gitwo's own config stays with the layout unless it is part of the
+	// checkout
+	keepGitwo := true
+	if out, err := gitIn(root, "ls-files", "--", ".gitwo"); err == nil && strings.TrimSpace(out) != "" {
+		keepGitwo = false
+	}
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		switch {
+		case e.Name() == ".git":
+		case e.Name() == ".gitwo" && keepGitwo:
+			plan.KeepDirs = append(plan.KeepDirs, e.Name())
+		default:
+			plan.Entries = append(plan.Entries, e.Name())
+		}
+	}
+
+	items, err := List()
+	if err != nil {
+		return nil, err
+	}
+	for _, it := range items {
+		if canonicalPath(it.Path) != canonicalPath(root) && !it.Prunable {
+			plan.Linked = append(plan.Linked, it.Path)
+		}
+	}
+	return plan, nil
+}
+
+// Steps describes the plan for --dry-run
+func (p *ConvertPlan) Steps() []string {
+	var steps []string
+	for _, s := range p.steps() {
+		steps = append(steps, s.desc)
+	}
+	return steps
+}
+
+// NewPath returns where a path under the root ends up after the conversion
+func (p *ConvertPlan) NewPath(path string) string {
+	rel, err := filepath.Rel(canonicalPath(p.Root), canonicalPath(path))
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return path
+	}
+	top := strings.SplitN(rel, string(filepath.Separator), 2)[0]
+	for _, e := range p.Entries {
+		if e == top {
+			return filepath.Join(p.Worktree, rel)
+		}
+	}
+	return path
+}
+
+type convertStep struct {
+	desc string
+	do   func() error
+	undo func() error
+}
+
+func (p *ConvertPlan) steps() []convertStep {
+	gitPath := filepath.Join(p.Root, ".git")
+	bare := filepath.Join(p.Root, bareDir)
+	staging := filepath.Join(p.Root, convertStaging)
+	scratch := filepath.Join(p.Root, convertScratch)
+	name := filepath.Base(p.Worktree)
+	var adminDir string // the new worktree's admin dir, known once it is registered
+
+	linked := make([]string, len(p.Linked))
+	for i, path := range p.Linked {
+		linked[i] = p.NewPath(path)
+	}
+
+	steps := []convertStep{
+		{
+			desc: fmt.Sprintf("move %s to %s", gitPath, bare),
+			do:   func() error { return os.Rename(gitPath, bare) },
+			undo: func() error {
+				if _, err := os.Stat(bare); err != nil {
+					return nil
+				}
+				return os.Rename(bare, gitPath)
+			},
+		},
+		{
+			desc: fmt.Sprintf("write %s pointing at %s and mark the repository bare", gitPath, bareDir),
+			do: func() error {
+				if err := os.WriteFile(gitPath, []byte("gitdir: ./"+bareDir+"\n"), 0o644); err != nil {
+					return err
+				}
+				if out, err := gitIn(bare, "config", "core.bare", "true"); err != nil {
+					return fmt.Errorf("failed to set core.bare: %s", lastLine(out))
+				}
+				return nil
+			},
+			undo: func() error {
+				if out, err := gitIn(bare, "config", "core.bare", "false"); err != nil {
+					return fmt.Errorf("failed to reset core.bare: %s", lastLine(out))
+				}
+				if err := os.Remove(gitPath); err != nil && !os.IsNotExist(err) {
+					return err
+				}
+				return nil
+			},
+		},
+		{
+			desc: fmt.Sprintf("move the checkout of %s (%d entries) into %s", p.Branch, len(p.Entries), p.Worktree),
+			do: func() error {
+				if err := os.Mkdir(staging, 0o755); err != nil {
+					return err
+				}
+				for _, e := range p.Entries {
+					if err := os.Rename(filepath.Join(p.Root, e), filepath.Join(staging, e)); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+			undo: func() error {
+				for _, e := range p.Entries {
+					if _, err := os.Lstat(filepath.Join(staging, e)); err != nil {
+						continue
+					}
+					if err := os.Rename(filepath.Join(staging, e), filepath.Join(p.Root, e)); err != nil {
+						return err
+					}
+				}
+				if err := os.Remove(staging); err != nil && !os.IsNotExist(err) {
+					return err
+				}
+				return nil
+			},
+		},
+		{
+			desc: fmt.Sprintf("register %s as the worktree for %s, keeping its index", p.Worktree, p.Branch),
+			do: func() error {
+				// git only adds worktrees in empty directories, so add one in
+				// a scratch directory and move its .git file over
+				tmp := filepath.Join(scratch, name)
+				if err := os.Mkdir(scratch, 0o755); err != nil {
+					return err
+				}
+				if out, err := gitIn(p.Root, "worktree", "add", "--quiet", "--no-checkout", tmp, p.Branch); err != nil {
+					return fmt.Errorf("git worktree add failed: %s", lastLine(out))
+				}
+				data, err := os.ReadFile(filepath.Join(tmp, ".git"))
+				if err != nil {
+					return err
+				}
+				adminDir = strings.TrimSpace(strings.TrimPrefix(string(data), "gitdir:"))
+				if err := os.Rename(filepath.Join(tmp, ".git"), filepath.Join(staging, ".git")); err != nil {
+					return err
+				}
+				if err := os.RemoveAll(scratch); err != nil {
+					return err
+				}
+				return os.Rename(filepath.Join(bare, "index"), filepath.Join(adminDir, "index"))
+			},
+			undo: func() error {
+				if adminDir != "" {
+					if _, err := os.Stat(filepath.Join(adminDir, "index")); err == nil {
+						if err := os.Rename(filepath.Join(adminDir, "index"), filepath.Join(bare, "index")); err != nil {
+							return err
+						}
+					}
+					if err := os.RemoveAll(adminDir); err != nil {
+						return err
+					}
+				}
+				_ = os.Remove(filepath.Join(staging, ".git"))
+				return os.RemoveAll(scratch)
+			},
+		},
+		{
+			desc: fmt.Sprintf("rename %s to %s and repair its links", staging, p.Worktree),
+			do: func() error {
+				if err := os.Rename(staging, p.Worktree); err != nil {
+					return err
+				}
+				if out, err := gitIn(p.Root, "worktree", "repair", p.Worktree); err != nil {
+					return fmt.Errorf("git worktree repair failed: %s", lastLine(out))
+				}
+				// The files were moved, so their stat data in the index is stale
+				_, _ = gitIn(p.Worktree, "update-index", "-q", "--refresh")
+				return nil
+			},
+			undo: func() error {
+				if _, err := os.Stat(p.Worktree); err != nil {
+					return nil
+				}
+				return os.Rename(p.Worktree, staging)
+			},
+		},
+	}
+	if len(linked) > 0 {
+		steps = append(steps, convertStep{
+			desc: fmt.Sprintf("repair the links of %d linked worktree(s): %s", len(linked), strings.Join(linked, ", ")),
+			do: func() error {
+				args := append([]string{"worktree", "repair"}, linked...)
+				if out, err := gitIn(p.Root, args...); err != nil {
+					return fmt.Errorf("git worktree repair failed: %s", lastLine(out))
+				}
+				return nil
+			},
+			// Convert repairs them again once the original layout is back
+			undo: func() error { return nil },
+		})
+	}
+	return steps
+}
+
+// Convert carries out the plan. When a step fails, the completed steps are
+// undone in reverse order so the original layout is restored.
+func Convert(p *ConvertPlan) error {
+	steps := p.steps()
+	for i, s := range steps {
+		err := s.do()
+		if err == nil && i == p.failAt {
+			err = fmt.Errorf("injected failure")
+		}
+		if err == nil {
+			continue
+		}
+		err = fmt.Errorf("convert failed (%s): %w", s.desc, err)
+		for j := i; j >= 0; j-- {
+			if uerr := steps[j].undo(); uerr != nil {
+				return fmt.Errorf("%w\nrestoring the original layout also failed (%s): %v", err, steps[j].desc, uerr)
+			}
+		}
+		if len(p.Linked) > 0 {
+			args := append([]string{"worktree", "repair"}, p.Linked...)
+			_, _ = gitIn(p.Root, args...)
+		}
+		return fmt.Errorf("%w\nthe original layout was restored", err)
+	}
+	return nil
+}
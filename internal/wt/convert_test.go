@@ -0,0 +1,117 @@
+package wt
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// convertFixture prepares a clone with a branch, a stash, a hook, a linked
+// worktree, a directory named like the branch and dirty changes
+func convertFixture(t *testing.T) (repo, linked string) {
+	t.Helper()
+	repo = newTestRepo(t)
+	require.NoError(t, os.MkdirAll(filepath.Join(repo, "main"), 0o755))
+	commitFile(t, repo, "main/app.go", "package main\n")
+	runGit(t, repo, "branch", "feature/x")
+	linked = filepath.Join(filepath.Dir(repo), "linked")
+	runGit(t, repo, "worktree", "add", "-q", linked, "feature/x")
+	writeFile(t, repo, "README.md", "# stashed\n")
+	runGit(t, repo, "stash", "-q")
+	writeFile(t, filepath.Join(repo, ".git", "hooks"), "pre-commit", "#!/bin/sh\n")
+
+	writeFile(t, repo, "README.md", "# changed\n")
+	writeFile(t, repo, "staged.txt", "staged\n")
+	runGit(t, repo, "add", "staged.txt")
+	writeFile(t, repo, "untracked.txt", "untracked\n")
+	return repo, linked
+}
+
+func TestConvert(t *testing.T) {
+	repo, linked := convertFixture(t)
+	statusBefore := runGit(t, repo, "status", "--porcelain")
+
+	plan, err := PlanConvert()
+	require.NoError(t, err)
+	assert.Equal(t, "main", plan.Branch)
+	assert.Equal(t, filepath.Join(repo, "main"), plan.Worktree)
+	assert.Equal(t, []string{linked}, plan.Linked)
+	assert.NotEmpty(t, plan.Steps())
+
+	require.NoError(t, Convert(plan))
+
+	gitFile, err := os.ReadFile(filepath.Join(repo, ".git"))
+	require.NoError(t, err)
+	assert.Equal(t, "gitdir: ./.bare\n", string(gitFile))
+	assert.Equal(t, "true", strings.TrimSpace(runGit(t, filepath.Join(repo, ".bare"), "config", "core.bare")))
+	assert.FileExists(t, filepath.Join(repo, ".bare", "hooks", "pre-commit"))
+
+	wtPath := plan.Worktree
+	assert.FileExists(t, filepath.Join(wtPath, "main", "app.go"))
+	assert.Equal(t, statusBefore, runGit(t, wtPath, "status", "--porcelain"))
+	assert.Equal(t, "main", strings.TrimSpace(runGit(t, wtPath, "branch", "--show-current")))
+	assert.Contains(t, runGit(t, wtPath, "stash", "list"), "stash@{0}")
+	runGit(t, wtPath, "rev-parse", "--verify", "feature/x")
+
+	assert.True(t, linksIntact(wtPath))
+	assert.True(t, linksIntact(linked))
+	assert.Equal(t, "feature/x", strings.TrimSpace(runGit(t, linked, "branch", "--show-current")))
+
+	t.Chdir(wtPath)
+	root, err := MainRoot()
+	require.NoError(t, err)
+	assert.Equal(t, repo, root)
+}
+
+func TestConvert_RollsBack(t *testing.T) {
+	repo, linked := convertFixture(t)
+	statusBefore := runGit(t, repo, "status", "--porcelain")
+
+	plan, err := PlanConvert()
+	require.NoError(t, err)
+	for i := range plan.Steps() {
+		t.Run(fmt.Sprintf("step %d", i+1), func(t *testing.T) {
+			plan.failAt = i
+			err := Convert(plan)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "the original layout was restored")
+
+			fi, err := os.Stat(filepath.Join(repo, ".git"))
+			require.NoError(t, err)
+			assert.True(t, fi.IsDir())
+			assert.NoDirExists(t, filepath.Join(repo, ".bare"))
+			assert.NoDirExists(t, filepath.Join(repo, convertStaging))
+			assert.Equal(t, statusBefore, runGit(t, repo, "status", "--porcelain"))
+			assert.Equal(t, "false", strings.TrimSpace(runGit(t, repo, "config", "core.bare")))
+			assert.True(t, linksIntact(linked))
+			assert.Contains(t, runGit(t, repo, "stash", "list"), "stash@{0}")
+			assert.Equal(t, 2, strings.Count(runGit(t, repo, "worktree", "list"), "\n"))
+		})
+	}
+}
+
+func TestPlanConvert_Refuses(t *testing.T) {
+	t.Run("already converted", func(t *testing.T) {
+		newTestRepo(t)
+		plan, err := PlanConvert()
+		require.NoError(t, err)
+		require.NoError(t, Convert(plan))
+
+		_, err = PlanConvert()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "already uses the bare layout")
+	})
+
+	t.Run("detached HEAD", func(t *testing.T) {
+		repo := newTestRepo(t)
+		runGit(t, repo, "checkout", "-q", "--detach")
+		_, err := PlanConvert()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "detached")
+	})
+}
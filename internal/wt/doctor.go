@@ -0,0 +1,296 @@
+package wt
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Severity ranks a gitwo doctor finding
+type Severity string
+
+const (
+	SeverityOK      Severity = "ok"
+	SeverityInfo    Severity = "info"
+	SeverityWarning Severity = "warning"
+	SeverityError   Severity = "error"
+)
+
+// Finding is the outcome of one gitwo doctor check
+type Finding struct {
+	Check    string // area checked, e.g. git, shell, config, worktree
+	Severity Severity
+	Message  string
+	Fix      string // what to do about it, "" when nothing needs doing
+	// Apply performs Fix. It is only set when the fix is safe to apply
+	// without asking, i.e. for gitwo doctor --fix.
+	Apply func() error
+}
+
+// staleLockAge is how old an index.lock must be before doctor treats it as
+// left behind by a crashed git rather than held by a running one
+const staleLockAge = 10 * time.Minute
+
+// GitVersion is a parsed `git version`
+type GitVersion struct {
+	Major, Minor, Patch int
+}
+
+func (v GitVersion) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// AtLeast reports whether v is major.minor or newer
+func (v GitVersion) AtLeast(major, minor int) bool {
+	return v.Major > major || (v.Major == major && v.Minor >= minor)
+}
+
+var gitVersionRe = regexp.MustCompile(`(\d+)\.(\d+)(?:\.(\d+))?`)
+
+// ParseGitVersion parses output such as "git version 2.39.5" or
+// "git version 2.45.1.windows.1"
+func ParseGitVersion(s string) (GitVersion, error) {
+	m := gitVersionRe.FindStringSubmatch(s)
+	if m == nil {
+		return GitVersion{}, fmt.Errorf("cannot parse git version from %q", strings.TrimSpace(s))
+	}
+	var v GitVersion
+	v.Major, _ = strconv.Atoi(m[1])
+	v.Minor, _ = strconv.Atoi(m[2])
+	v.Patch, _ = strconv.Atoi(m[3])
+	return v, nil
+}
+
+// gitFeatures are the worktree features gitwo relies on and the git release
+// that added them. The first one is required.
+var gitFeatures = []struct {
+	major, minor int
+	desc         string
+}{
+	{2, 7, "git worktree list --porcelain"},
+	{2, 17, "worktree move and remove (gitwo move, remove)"},
+	{2, 25, "sparse-checkout (gitwo sparse, new --sparse)"},
+	{2, 29, "worktree repair (gitwo convert)"},
+	{2, 31, "prunable worktrees in git worktree list (gitwo prune)"},
+}
+
+// CheckGitVersion reports the installed git and the worktree features it
+// lacks
+func CheckGitVersion() []Finding {
+	out, err := runOut("git", "version")
+	if err != nil {
+		return []Finding{{Check: "git", Severity: SeverityError, Message: "git not found on PATH", Fix: "install git 2.31 or later"}}
+	}
+	v, err := ParseGitVersion(string(out))
+	if err != nil {
+		return []Finding{{Check: "git", Severity: SeverityWarning, Message: err.Error()}}
+	}
+	var missing []string
+	for _, f := range gitFeatures {
+		if !v.AtLeast(f.major, f.minor) {
+			missing = append(missing, fmt.Sprintf("%s (%d.%d)", f.desc, f.major, f.minor))
+		}
+	}
+	last := gitFeatures[len(gitFeatures)-1]
+	upgrade := fmt.Sprintf("upgrade git to %d.%d or later", last.major, last.minor)
+	switch {
+	case len(missing) == 0:
+		return []Finding{{Check: "git", Severity: SeverityOK, Message: fmt.Sprintf("git %s supports every worktree feature gitwo uses", v)}}
+	case len(missing) == len(gitFeatures):
+		return []Finding{{Check: "git", Severity: SeverityError, Message: fmt.Sprintf("git %s is too old for gitwo, which needs %s", v, missing[0]), Fix: upgrade}}
+	}
+	return []Finding{{Check: "git", Severity: SeverityWarning, Message: fmt.Sprintf("git %s lacks %s", v, strings.Join(missing, ", ")), Fix: upgrade}}
+}
+
+// CheckRepository checks the worktrees of the current repository and looks
+// for leftover ones under parents (the worktrees dir). Fixes are returned in
+// the order they can be applied: repairs of moved worktrees come before the
+// prune that would otherwise drop their admin dirs.
+func CheckRepository(parents []string) ([]Finding, error) {
+	items, err := List()
+	if err != nil {
+		return nil, err
+	}
+	findings := checkOrphans(items, parents)
+	return append(findings, checkWorktrees(items)...), nil
+}
+
+// checkWorktrees checks the worktrees git knows about: directories that are
+// gone, broken .git links, gitwo metadata recorded for another path and
+// stale index.lock files
+func checkWorktrees(items []WorktreeItem) []Finding {
+	var findings []Finding
+	root, _ := MainRoot()
+	for _, it := range items {
+		if it.Bare {
+			continue
+		}
+		path := it.Path
+		if it.Prunable {
+			findings = append(findings, Finding{
+				Check:    "worktree",
+				Severity: SeverityWarning,
+				Message:  fmt.Sprintf("%s no longer exists but git still lists it", path),
+				Fix:      "git worktree prune",
+				Apply: func() error {
+					_, err := gitIn(root, "worktree", "prune")
+					return err
+				},
+			})
+			continue
+		}
+		// Only linked worktrees have a .git file linking them to their admin dir
+		if fi, err := os.Stat(filepath.Join(path, ".git")); err == nil && !fi.IsDir() && !linksIntact(path) {
+			findings = append(findings, Finding{
+				Check:    "worktree",
+				Severity: SeverityError,
+				Message:  fmt.Sprintf("the .git link of %s is broken (was the repository moved?)", path),
+				Fix:      "git worktree repair " + path,
+				Apply:    func() error { return repairWorktree(root, path) },
+			})
+			continue
+		}
+		if m, err := ReadMeta(path); err != nil {
+			findings = append(findings, Finding{Check: "worktree", Severity: SeverityWarning, Message: err.Error(), Fix: "delete " + metaFile + " from the worktree's git dir"})
+		} else if m.Path != "" && canonicalPath(m.Path) != canonicalPath(path) {
+			findings = append(findings, Finding{
+				Check:    "worktree",
+				Severity: SeverityInfo,
+				Message:  fmt.Sprintf("%s was moved from %s without gitwo", path, m.Path),
+				Fix:      "record the new path in gitwo's metadata",
+				Apply:    func() error { return UpdateMeta(path, func(*Meta) {}) },
+			})
+		}
+
+		gitDir, err := worktreeGitDir(path)
+		if err != nil {
+			continue
+		}
+		lock := filepath.Join(gitDir, "index.lock")
+		fi, err := os.Stat(lock)
+		if err != nil {
+			continue
+		}
+		age := time.Since(fi.ModTime()).Round(time.Second)
+		if age < staleLockAge {
+			findings = append(findings, Finding{Check: "worktree", Severity: SeverityInfo, Message: fmt.Sprintf("%s has an index.lock from %s ago; a git command may be running", path, age)})
+			continue
+		}
+		findings = append(findings, Finding{
+			Check:    "worktree",
+			Severity: SeverityWarning,
+			Message:  fmt.Sprintf("%s has a stale index.lock (%s old) that makes git commands fail", path, age),
+			Fix:      "remove " + lock,
+			Apply:    func() error { return os.Remove(lock) },
+		})
+	}
+	return findings
+}
+
+// checkOrphans looks for directories under parents, and one level further
+// down, that are worktrees of this repository git does not list. Moved
+// worktrees whose admin dir still exists can be repaired; copies and
+// worktrees git has forgotten are left for the user to clean up.
+func checkOrphans(items []WorktreeItem, parents []string) []Finding {
+	common, err := commonDir()
+	if err != nil {
+		return nil
+	}
+	adminRoot := canonicalPath(filepath.Join(common, "worktrees")) + string(filepath.Separator)
+	root, _ := MainRoot()
+	known := map[string]bool{}
+	for _, it := range items {
+		known[canonicalPath(it.Path)] = true
+	}
+
+	var findings []Finding
+	seen := map[string]bool{}
+	var scan func(dir string, depth int)
+	scan = func(dir string, depth int) {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return
+		}
+		for _, e := range entries {
+			if !e.IsDir() {
+				continue
+			}
+			path := filepath.Join(dir, e.Name())
+			canon := canonicalPath(path)
+			if known[canon] || seen[canon] {
+				continue
+			}
+			seen[canon] = true
+			data, err := os.ReadFile(filepath.Join(path, ".git"))
+			if err != nil {
+				if _, statErr := os.Stat(filepath.Join(path, ".git")); os.IsNotExist(statErr) && depth < 1 {
+					scan(path, depth+1)
+				}
+				continue
+			}
+			adminDir := strings.TrimSpace(strings.TrimPrefix(string(data), "gitdir:"))
+			if !filepath.IsAbs(adminDir) {
+				adminDir = filepath.Join(path, adminDir)
+			}
+			if !strings.HasPrefix(canonicalPath(adminDir)+string(filepath.Separator), adminRoot) {
+				continue // a worktree of another repository
+			}
+			if owner := adminDirOwner(adminDir); owner != "" && known[canonicalPath(owner)] {
+				findings = append(findings, Finding{
+					Check:    "worktree",
+					Severity: SeverityWarning,
+					Message:  fmt.Sprintf("%s is a copy of the worktree %s", path, owner),
+					Fix:      "copy out anything you still need, then delete " + path,
+				})
+				continue
+			}
+			if _, err := os.Stat(adminDir); err == nil {
+				findings = append(findings, Finding{
+					Check:    "worktree",
+					Severity: SeverityWarning,
+					Message:  fmt.Sprintf("%s was moved without git worktree move; git lists it elsewhere", path),
+					Fix:      "git worktree repair " + path,
+					Apply:    func() error { return repairWorktree(root, path) },
+				})
+				continue
+			}
+			findings = append(findings, Finding{
+				Check:    "worktree",
+				Severity: SeverityWarning,
+				Message:  fmt.Sprintf("%s is a leftover worktree of this repository that git no longer knows about", path),
+				Fix:      "copy out anything you still need, then delete " + path,
+			})
+		}
+	}
+	for _, p := range parents {
+		scan(p, 0)
+	}
+	return findings
+}
+
+// adminDirOwner returns the worktree a worktree admin dir links back to
+func adminDirOwner(adminDir string) string {
+	data, err := os.ReadFile(filepath.Join(adminDir, "gitdir"))
+	if err != nil {
+		return ""
+	}
+	back := strings.TrimSpace(string(data))
+	if !filepath.IsAbs(back) {
+		back = filepath.Join(adminDir, back)
+	}
+	if _, err := os.Stat(back); err != nil {
+		return ""
+	}
+	return filepath.Dir(back)
+}
+
+func repairWorktree(root, path string) error {
+	if out, err := gitIn(root, "worktree", "repair", path); err != nil {
+		return fmt.Errorf("git worktree repair failed: %s", lastLine(out))
+	}
+	return nil
+}
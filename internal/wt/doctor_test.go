@@ -0,0 +1,118 @@
+package wt
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseGitVersion(t *testing.T) {
+	for in, want := range map[string]GitVersion{
+		"git version 2.39.5\n":               {2, 39, 5},
+		"git version 2.45.1.windows.1":       {2, 45, 1},
+		"git version 2.39.3 (Apple Git-146)": {2, 39, 3},
+		"git version 2.17":                   {2, 17, 0},
+	} {
+		v, err := ParseGitVersion(in)
+		require.NoError(t, err, in)
+		assert.Equal(t, want, v, in)
+	}
+	_, err := ParseGitVersion("not git")
+	assert.Error(t, err)
+
+	v := GitVersion{2, 17, 1}
+	assert.True(t, v.AtLeast(2, 17))
+	assert.True(t, v.AtLeast(1, 40))
+	assert.False(t, v.AtLeast(2, 25))
+}
+
+// findingFor returns the finding whose message mentions path
+func findingFor(t *testing.T, findings []Finding, path string) Finding {
+	t.Helper()
+	for _, f := range findings {
+		if strings.Contains(f.Message, path+" ") {
+			return f
+		}
+	}
+	t.Fatalf("no finding for %s in %+v", path, findings)
+	return Finding{}
+}
+
+func TestCheckRepository(t *testing.T) {
+	repo := newTestRepo(t)
+	parent := filepath.Dir(repo)
+	add := func(name string) string {
+		path := filepath.Join(parent, name)
+		runGit(t, repo, "worktree", "add", "-q", "-b", name, path)
+		return path
+	}
+	moved, gone, locked, relocated := add("moved"), add("gone"), add("locked"), add("relocated")
+	require.NoError(t, WriteMeta(relocated, Meta{}))
+
+	require.NoError(t, os.Rename(moved, moved+"-new"))
+	require.NoError(t, os.RemoveAll(gone))
+	lock := filepath.Join(repo, ".git", "worktrees", "locked", "index.lock")
+	writeFile(t, filepath.Dir(lock), "index.lock", "")
+	old := time.Now().Add(-time.Hour)
+	require.NoError(t, os.Chtimes(lock, old, old))
+	// Moved by hand and repaired: git is fine, gitwo's metadata is not
+	require.NoError(t, os.Rename(relocated, relocated+"-new"))
+	runGit(t, repo, "worktree", "repair", relocated+"-new")
+	// Forgotten by git
+	forgotten := add("forgotten")
+	require.NoError(t, os.RemoveAll(filepath.Join(repo, ".git", "worktrees", "forgotten")))
+
+	findings, err := CheckRepository([]string{parent})
+	require.NoError(t, err)
+
+	f := findingFor(t, findings, moved+"-new")
+	assert.Contains(t, f.Message, "moved without git worktree move")
+	require.NotNil(t, f.Apply)
+	assert.Nil(t, findingFor(t, findings, forgotten).Apply)
+	assert.Contains(t, findingFor(t, findings, gone).Fix, "git worktree prune")
+	assert.Contains(t, findingFor(t, findings, locked).Message, "stale index.lock")
+	assert.Equal(t, SeverityInfo, findingFor(t, findings, relocated+"-new").Severity)
+
+	for _, f := range findings {
+		if f.Apply != nil {
+			require.NoError(t, f.Apply(), f.Message)
+		}
+	}
+	findings, err = CheckRepository([]string{parent})
+	require.NoError(t, err)
+	require.Len(t, findings, 1, "only the forgotten worktree is left: %+v", findings)
+	assert.Contains(t, findings[0].Message, forgotten)
+
+	items, err := List()
+	require.NoError(t, err)
+	paths := map[string]bool{}
+	for _, it := range items {
+		paths[canonicalPath(it.Path)] = true
+	}
+	assert.True(t, paths[canonicalPath(moved+"-new")])
+	assert.False(t, paths[canonicalPath(gone)])
+	assert.NoFileExists(t, lock)
+}
+
+func TestCheckRepository_MovedRepository(t *testing.T) {
+	repo := newTestRepo(t)
+	feature := filepath.Join(filepath.Dir(repo), "feature")
+	runGit(t, repo, "worktree", "add", "-q", "-b", "feature", feature)
+
+	moved := repo + "-moved"
+	require.NoError(t, os.Rename(repo, moved))
+	t.Chdir(moved)
+
+	findings, err := CheckRepository(nil)
+	require.NoError(t, err)
+	f := findingFor(t, findings, feature)
+	assert.Equal(t, SeverityError, f.Severity)
+	require.NotNil(t, f.Apply)
+	require.NoError(t, f.Apply())
+	assert.True(t, linksIntact(feature))
+}
@@ -0,0 +1,70 @@
+package wt
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// FuzzyScore reports whether all runes of pattern appear in s in order,
+// ignoring case, and scores the match. Consecutive runes and runes at the
+// start of a word (after / - _ . or at the beginning) score higher.
+func FuzzyScore(pattern, s string) (int, bool) {
+	p := []rune(strings.ToLower(pattern))
+	r := []rune(strings.ToLower(s))
+	if len(p) == 0 {
+		return 0, true
+	}
+
+	score, pi, prev := 0, 0, -2
+	for i := 0; i < len(r) && pi < len(p); i++ {
+		if r[i] != p[pi] {
+			continue
+		}
+		score++
+		if i == prev+1 {
+			score += 2
+		}
+		if i == 0 || !unicode.IsLetter(r[i-1]) && !unicode.IsDigit(r[i-1]) {
+			score += 3
+		}
+		prev = i
+		pi++
+	}
+	if pi < len(p) {
+		return 0, false
+	}
+	// Prefer shorter names when scores tie
+	return score*100 - len(r), true
+}
+
+// FuzzyFilter returns the items whose directory or branch name fuzzy-matches
+// query, best match first. An empty query returns all non-bare items.
+func FuzzyFilter(items []WorktreeItem, query string) []WorktreeItem {
+	type scored struct {
+		item  WorktreeItem
+		score int
+	}
+	var found []scored
+	for _, it := range items {
+		if it.Bare {
+			continue
+		}
+		best, ok := 0, false
+		for _, name := range worktreeNames(it) {
+			if s, match := FuzzyScore(query, name); match && (!ok || s > best) {
+				best, ok = s, true
+			}
+		}
+		if ok {
+			found = append(found, scored{it, best})
+		}
+	}
+	sort.SliceStable(found, func(i, j int) bool { return found[i].score > found[j].score })
+
+	out := make([]WorktreeItem, len(found))
+	for i, f := range found {
+		out[i] = f.item
+	}
+	return out
+}
@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"bytes"
 	"fmt"
+	"path/filepath"
 	"strings"
 )
 
@@ -11,6 +12,16 @@ type WorktreeItem struct {
 	Path   string
 	Head   string
 	Branch string // short name, no refs/heads/
+	// Ref describes a detached HEAD: a tag pointing at it, the ref it was
+	// checked out from, or the abbreviated commit
+	Ref string
+
+	Detached       bool
+	Bare           bool
+	Locked         bool
+	LockReason     string
+	Prunable       bool
+	PrunableReason string
 }
 
 func (w WorktreeItem) String() string {
@@ -44,10 +55,132 @@ func List() ([]WorktreeItem, error) {
 		case strings.HasPrefix(line, "branch "):
 			ref := strings.TrimSpace(strings.TrimPrefix(line, "branch "))
 			cur.Branch = strings.TrimPrefix(ref, "refs/heads/")
+		case line == "detached":
+			cur.Detached = true
+		case line == "bare":
+			cur.Bare = true
+		case line == "locked" || strings.HasPrefix(line, "locked "):
+			cur.Locked = true
+			cur.LockReason = strings.TrimSpace(strings.TrimPrefix(line, "locked"))
+		case line == "prunable" || strings.HasPrefix(line, "prunable "):
+			cur.Prunable = true
+			cur.PrunableReason = strings.TrimSpace(strings.TrimPrefix(line, "prunable"))
 		}
 	}
 	if cur.Path != "" {
 		items = append(items, cur)
 	}
+	describeDetached(items)
 	return items, nil
 }
+
+// BranchColumn is what list shows as a worktree's branch: the branch, or the
+// ref of a detached HEAD in parentheses
+func (w WorktreeItem) BranchColumn() string {
+	if w.Detached && w.Ref != "" {
+		return "(" + w.Ref + ")"
+	}
+	return w.Branch
+}
+
+// describeDetached fills in Ref for detached worktrees. Tags win; otherwise
+// the ref gitwo recorded when creating the worktree, as long as HEAD has not
+// moved; otherwise the short commit id.
+func describeDetached(items []WorktreeItem) {
+	var tags map[string]string
+	for i := range items {
+		it := &items[i]
+		if !it.Detached || it.Head == "" {
+			continue
+		}
+		if tags == nil {
+			tags = tagsByCommit()
+		}
+		if tag, ok := tags[it.Head]; ok {
+			it.Ref = tag
+			continue
+		}
+		it.Ref = ShortSHA(it.Head)
+		if m, err := ReadMeta(it.Path); err == nil {
+			if d := m.DetachedAt(); d != nil && d.Head == it.Head {
+				it.Ref = d.Ref
+			}
+		}
+	}
+}
+
+// tagsByCommit maps commits to the (first, by name) tag pointing at them
+func tagsByCommit() map[string]string {
+	tags := map[string]string{}
+	out, err := gitOut("for-each-ref", "--format=%(objectname) %(*objectname) %(refname:short)", "refs/tags")
+	if err != nil {
+		return tags
+	}
+	sc := bufio.NewScanner(bytes.NewReader(out))
+	for sc.Scan() {
+		fields := strings.Fields(sc.Text())
+		var commit, tag string
+		switch len(fields) {
+		case 2: // lightweight tag
+			commit, tag = fields[0], fields[1]
+		case 3: // annotated tag: the peeled object is the commit
+			commit, tag = fields[1], fields[2]
+		default:
+			continue
+		}
+		if _, seen := tags[commit]; !seen {
+			tags[commit] = tag
+		}
+	}
+	return tags
+}
+
+// CurrentIndex returns the index of the worktree that contains the current
+// directory, or -1 when the current directory is not inside any of them.
+func CurrentIndex(items []WorktreeItem) int {
+	top, err := repoRoot()
+	if err != nil {
+		return -1
+	}
+	top = canonicalPath(top)
+	for i, it := range items {
+		if canonicalPath(it.Path) == top {
+			return i
+		}
+	}
+	return -1
+}
+
+// FindByPath returns the worktree registered at path, or nil when git does not
+// know about a worktree there.
+func FindByPath(path string) (*WorktreeItem, error) {
+	items, err := List()
+	if err != nil {
+		return nil, err
+	}
+	target := canonicalPath(path)
+	for i := range items {
+		if canonicalPath(items[i].Path) == target {
+			return &items[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// canonicalPath returns an absolute, symlink-free form of path so that paths
+// reported by git can be compared with user input. Paths that do not exist
+// yet are resolved through their closest existing parent.
+func canonicalPath(path string) string {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return filepath.Clean(path)
+	}
+	if resolved, err := filepath.EvalSymlinks(abs); err == nil {
+		return resolved
+	}
+	parent := filepath.Dir(abs)
+	if parent == abs {
+		return abs
+	}
+	return filepath.Join(canonicalPath(parent), filepath.Base(abs))
+}
@@ -133,3 +133,61 @@ func TestList_EmptyRepo(t *testing.T) {
 	// Use contains check instead of exact match to handle path differences
 	assert.Contains(t, items[0].Path, "TestList_EmptyRepo")
 }
+
+func TestList_DetachedAndCurrent(t *testing.T) {
+	repo := newTestRepo(t)
+	detached := filepath.Join(filepath.Dir(repo), "detached")
+	runGit(t, repo, "worktree", "add", "-q", "--detach", detached, "HEAD")
+
+	items, err := List()
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+	assert.False(t, items[0].Detached)
+	assert.True(t, items[1].Detached)
+	assert.Empty(t, items[1].Branch)
+	assert.Equal(t, 0, CurrentIndex(items))
+
+	t.Chdir(detached)
+	assert.Equal(t, 1, CurrentIndex(items))
+}
+
+func TestList_DetachedRef(t *testing.T) {
+	repo := newTestRepo(t)
+	runGit(t, repo, "tag", "v1.0")
+	commitFile(t, repo, "a.txt", "a\n")
+	runGit(t, repo, "tag", "-a", "-m", "release", "v1.1")
+	commitFile(t, repo, "b.txt", "b\n")
+	commitFile(t, repo, "c.txt", "c\n")
+	parent := filepath.Dir(repo)
+
+	runGit(t, repo, "worktree", "add", "-q", "--detach", filepath.Join(parent, "light"), "v1.0")
+	runGit(t, repo, "worktree", "add", "-q", "--detach", filepath.Join(parent, "annotated"), "v1.1")
+	_, err := AddDetached(filepath.Join(parent, "recorded"), "main", nil, true)
+	require.NoError(t, err)
+	head := strings.TrimSpace(runGit(t, repo, "rev-parse", "main"))
+	require.NoError(t, WriteMeta(filepath.Join(parent, "recorded"), Meta{Detached: &DetachedMeta{Ref: "main", Head: head}}))
+	runGit(t, repo, "worktree", "add", "-q", "--detach", filepath.Join(parent, "plain"), "main~1")
+
+	items, err := List()
+	require.NoError(t, err)
+	refs := map[string]string{}
+	for _, it := range items {
+		refs[filepath.Base(it.Path)] = it.BranchColumn()
+	}
+	assert.Equal(t, "main", refs["repo"])
+	assert.Equal(t, "(v1.0)", refs["light"])
+	assert.Equal(t, "(v1.1)", refs["annotated"])
+	assert.Equal(t, "(main)", refs["recorded"])
+	// Neither a tag nor a recorded ref: the short commit
+	assert.Equal(t, "("+ShortSHA(strings.TrimSpace(runGit(t, repo, "rev-parse", "main~1")))+")", refs["plain"])
+
+	// A recorded ref no longer applies once HEAD moves
+	commitFile(t, filepath.Join(parent, "recorded"), "d.txt", "d\n")
+	items, err = List()
+	require.NoError(t, err)
+	for _, it := range items {
+		if filepath.Base(it.Path) == "recorded" {
+			assert.Equal(t, "detached at "+ShortSHA(it.Head), it.Label())
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package wt
+
+import (
+	"fmt"
+	"strings"
+)
+
+// LockedError is returned when a destructive operation hits a locked worktree
+type LockedError struct {
+	Path   string
+	Reason string
+}
+
+func (e *LockedError) Error() string {
+	msg := fmt.Sprintf("%s is locked", e.Path)
+	if e.Reason != "" {
+		msg += fmt.Sprintf(" (%s)", e.Reason)
+	}
+	return msg + "\nunlock it with 'gitwo unlock' or use --force"
+}
+
+// Lock marks a worktree as locked so git and gitwo will not prune, move or
+// remove it. The reason is optional.
+func Lock(item WorktreeItem, reason string) error {
+	if item.Locked {
+		return fmt.Errorf("%s is already locked%s", item.Path, lockSuffix(item.LockReason))
+	}
+	args := []string{"worktree", "lock"}
+	if reason = strings.TrimSpace(reason); reason != "" {
+		args = append(args, "--reason", reason)
+	}
+	if out, err := gitIn(".", append(args, item.Path)...); err != nil {
+		return fmt.Errorf("failed to lock %s: %s", item.Path, strings.TrimSpace(out))
+	}
+	return nil
+}
+
+// Unlock removes the lock from a worktree
+func Unlock(item WorktreeItem) error {
+	if !item.Locked {
+		return fmt.Errorf("%s is not locked", item.Path)
+	}
+	if out, err := gitIn(".", "worktree", "unlock", item.Path); err != nil {
+		return fmt.Errorf("failed to unlock %s: %s", item.Path, strings.TrimSpace(out))
+	}
+	return nil
+}
+
+// LockSummary renders the lock state for display, e.g. "locked (usb drive)"
+func (w WorktreeItem) LockSummary() string {
+	if !w.Locked {
+		return ""
+	}
+	return "locked" + lockSuffix(w.LockReason)
+}
+
+func lockSuffix(reason string) string {
+	if reason == "" {
+		return ""
+	}
+	return fmt.Sprintf(" (%s)", reason)
+}
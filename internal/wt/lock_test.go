@@ -0,0 +1,61 @@
+package wt
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLockUnlock(t *testing.T) {
+	repo := newTestRepo(t)
+	path := filepath.Join(filepath.Dir(repo), "release")
+	runGit(t, repo, "worktree", "add", "-q", "-b", "release/2.4", path, "main")
+
+	item, err := Resolve("release")
+	require.NoError(t, err)
+	require.NoError(t, Lock(item, "long-lived release"))
+
+	item, err = Resolve("release")
+	require.NoError(t, err)
+	assert.True(t, item.Locked)
+	assert.Equal(t, "locked (long-lived release)", item.LockSummary())
+	assert.Error(t, Lock(item, "again"), "locking twice is an error")
+
+	t.Run("remove refuses locked worktrees unless forced", func(t *testing.T) {
+		err := Remove(path)
+		var locked *LockedError
+		require.ErrorAs(t, err, &locked)
+		assert.Equal(t, "long-lived release", locked.Reason)
+		assert.DirExists(t, path)
+	})
+
+	t.Run("prune skips locked worktrees unless included", func(t *testing.T) {
+		commitFile(t, path, "fix.txt", "fix")
+		runGit(t, repo, "merge", "-q", "--no-ff", "-m", "Merge release", "release/2.4")
+
+		candidates, err := FindPruneCandidates(PruneOptions{MainBranch: "main"})
+		require.NoError(t, err)
+		require.Len(t, candidates, 1)
+		assert.Equal(t, "locked (long-lived release)", candidates[0].Blocked)
+
+		candidates, err = FindPruneCandidates(PruneOptions{MainBranch: "main", IncludeLocked: true})
+		require.NoError(t, err)
+		require.Len(t, candidates, 1)
+		assert.Empty(t, candidates[0].Blocked)
+	})
+
+	require.NoError(t, Unlock(item))
+	item, err = Resolve("release")
+	require.NoError(t, err)
+	assert.False(t, item.Locked)
+	assert.Error(t, Unlock(item), "unlocking an unlocked worktree is an error")
+
+	require.NoError(t, Lock(item, ""))
+	writeFile(t, path, "notes.txt", "draft")
+	var unsafe *UnsafeRemovalError
+	require.ErrorAs(t, RemoveWithOptions(path, RemoveOptions{IgnoreLock: true}), &unsafe, "IgnoreLock still protects work")
+	require.NoError(t, RemoveWithOptions(path, RemoveOptions{Force: true}))
+	assert.NoDirExists(t, path)
+}
@@ -0,0 +1,110 @@
+package wt
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// metaFile is stored in each worktree's own git dir (.git/worktrees/<id>/),
+// so it follows the worktree through `git worktree move` and disappears
+// with `git worktree remove`.
+const metaFile = "gitwo.json"
+
+// Meta is what gitwo remembers about a worktree beyond what git records
+type Meta struct {
+	// Path is where the worktree was when gitwo last wrote its metadata.
+	// A mismatch means it was moved without gitwo.
+	Path    string    `json:"path"`
+	Created time.Time `json:"created,omitempty"`
+	// PR is set for worktrees created by gitwo checkout-pr
+	PR *PRMeta `json:"pr,omitempty"`
+	// Ephemeral is set for temporary worktrees such as gitwo review's
+	Ephemeral *EphemeralMeta `json:"ephemeral,omitempty"`
+	// Detached is set for worktrees created by gitwo add --detach
+	Detached *DetachedMeta `json:"detached,omitempty"`
+}
+
+// DetachedMeta remembers which ref a detached worktree was created from
+type DetachedMeta struct {
+	Ref  string `json:"ref"`
+	Head string `json:"head"` // commit Ref pointed to at the time
+}
+
+// DetachedAt returns the ref and commit a detached worktree was created at,
+// from gitwo add --detach or gitwo review, or nil when unknown
+func (m Meta) DetachedAt() *DetachedMeta {
+	switch {
+	case m.Detached != nil:
+		return m.Detached
+	case m.Ephemeral != nil:
+		return &DetachedMeta{Ref: m.Ephemeral.Ref, Head: m.Ephemeral.Head}
+	}
+	return nil
+}
+
+// PRMeta remembers which pull/merge request a worktree tracks
+type PRMeta struct {
+	Number int    `json:"number"`
+	Remote string `json:"remote"`
+	Ref    string `json:"ref"`  // e.g. refs/pull/123/head
+	Head   string `json:"head"` // commit last checked out from Ref
+}
+
+// ReadMeta loads the metadata of the worktree at path. Worktrees without
+// metadata yield a zero Meta.
+func ReadMeta(path string) (Meta, error) {
+	var m Meta
+	dir, err := worktreeGitDir(path)
+	if err != nil {
+		return m, err
+	}
+	data, err := os.ReadFile(filepath.Join(dir, metaFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return m, nil
+	}
+	if err != nil {
+		return m, err
+	}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return m, fmt.Errorf("invalid gitwo metadata for %s: %w", path, err)
+	}
+	return m, nil
+}
+
+// WriteMeta stores the metadata of the worktree at path, recording path in it
+func WriteMeta(path string, m Meta) error {
+	dir, err := worktreeGitDir(path)
+	if err != nil {
+		return err
+	}
+	m.Path = path
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, metaFile), append(data, '\n'), 0o644)
+}
+
+// UpdateMeta reads, changes and writes back the metadata of a worktree
+func UpdateMeta(path string, update func(*Meta)) error {
+	m, err := ReadMeta(path)
+	if err != nil {
+		return err
+	}
+	update(&m)
+	return WriteMeta(path, m)
+}
+
+// worktreeGitDir returns the absolute git dir of the worktree at path
+func worktreeGitDir(path string) (string, error) {
+	out, err := gitOut("-C", path, "rev-parse", "--absolute-git-dir")
+	if err != nil {
+		return "", fmt.Errorf("not a git worktree: %s", path)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
@@ -0,0 +1,155 @@
+package wt
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// SubmoduleError explains why a worktree with submodules cannot be moved
+type SubmoduleError struct {
+	Path       string
+	Submodules []string
+}
+
+func (e *SubmoduleError) Error() string {
+	return fmt.Sprintf(`cannot move %s: it has initialized submodules (%s)
+git keeps their repositories inside the worktree's admin directory and cannot
+relocate them, so 'git worktree move' refuses. To move it anyway:
+  git -C %s submodule deinit --all
+  gitwo move ...
+  git -C <new-path> submodule update --init`,
+		e.Path, strings.Join(e.Submodules, ", "), e.Path)
+}
+
+// MoveOptions controls Move
+type MoveOptions struct {
+	Force bool // move locked worktrees too
+}
+
+// Move relocates a linked worktree to dest with `git worktree move`. When
+// dest is an existing directory the worktree is moved inside it. Afterwards
+// the gitdir links are checked (and repaired if needed) and gitwo's metadata
+// is updated. It returns the new path.
+func Move(item WorktreeItem, dest string, opts MoveOptions) (string, error) {
+	target, err := moveTarget(item, dest, opts)
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", filepath.Dir(target), err)
+	}
+
+	args := []string{"worktree", "move"}
+	if item.Locked {
+		args = append(args, "--force", "--force")
+	}
+	if out, err := gitIn(".", append(args, item.Path, target)...); err != nil {
+		return "", fmt.Errorf("git worktree move failed: %s", strings.TrimSpace(out))
+	}
+
+	if !linksIntact(target) {
+		if out, err := gitIn(".", "worktree", "repair", target); err != nil {
+			return target, fmt.Errorf("moved to %s, but git worktree repair failed: %s", target, strings.TrimSpace(out))
+		}
+	}
+
+	if err := UpdateMeta(target, func(*Meta) {}); err != nil {
+		return target, fmt.Errorf("moved to %s, but failed to update gitwo metadata: %w", target, err)
+	}
+	return target, nil
+}
+
+// CheckMove returns the error Move would fail with before it changes
+// anything, so callers can refuse early
+func CheckMove(item WorktreeItem, dest string, opts MoveOptions) error {
+	_, err := moveTarget(item, dest, opts)
+	return err
+}
+
+// moveTarget validates a move and returns the absolute path the worktree
+// ends up at
+func moveTarget(item WorktreeItem, dest string, opts MoveOptions) (string, error) {
+	items, err := List()
+	if err != nil {
+		return "", err
+	}
+	if len(items) > 0 && canonicalPath(items[0].Path) == canonicalPath(item.Path) && !items[0].Bare {
+		return "", fmt.Errorf("%s is the main worktree; git can only move linked worktrees", item.Path)
+	}
+	if item.Locked && !opts.Force {
+		return "", &LockedError{Path: item.Path, Reason: item.LockReason}
+	}
+
+	target, err := filepath.Abs(dest)
+	if err != nil {
+		return "", err
+	}
+	if fi, err := os.Stat(target); err == nil {
+		if !fi.IsDir() {
+			return "", fmt.Errorf("%s already exists", target)
+		}
+		target = filepath.Join(target, filepath.Base(item.Path))
+		if _, err := os.Stat(target); err == nil {
+			return "", fmt.Errorf("%s already exists", target)
+		}
+	}
+	if canonicalPath(target) == canonicalPath(item.Path) {
+		return "", fmt.Errorf("%s is already at %s", item.Label(), item.Path)
+	}
+
+	if subs := populatedSubmodules(item.Path); len(subs) > 0 {
+		return "", &SubmoduleError{Path: item.Path, Submodules: subs}
+	}
+	return target, nil
+}
+
+// populatedSubmodules lists the initialized submodules of the worktree at path
+func populatedSubmodules(path string) []string {
+	if _, err := os.Stat(filepath.Join(path, ".gitmodules")); err != nil {
+		return nil
+	}
+	out, err := gitOut("-C", path, "submodule", "status")
+	if err != nil {
+		return nil
+	}
+	var subs []string
+	sc := bufio.NewScanner(bytes.NewReader(out))
+	for sc.Scan() {
+		line := sc.Text()
+		// "-<sha> path" marks submodules that are not initialized
+		if line == "" || line[0] == '-' {
+			continue
+		}
+		if fields := strings.Fields(line[1:]); len(fields) >= 2 {
+			subs = append(subs, fields[1])
+		}
+	}
+	return subs
+}
+
+// linksIntact reports whether the worktree's .git file and its admin dir's
+// gitdir file point at each other
+func linksIntact(path string) bool {
+	data, err := os.ReadFile(filepath.Join(path, ".git"))
+	if err != nil {
+		return false
+	}
+	adminDir := strings.TrimSpace(strings.TrimPrefix(string(data), "gitdir:"))
+	if !filepath.IsAbs(adminDir) {
+		adminDir = filepath.Join(path, adminDir)
+	}
+	back, err := os.ReadFile(filepath.Join(adminDir, "gitdir"))
+	if err != nil {
+		return false
+	}
+	backPath := strings.TrimSpace(string(back))
+	if !filepath.IsAbs(backPath) {
+		backPath = filepath.Join(adminDir, backPath)
+	}
+	return canonicalPath(backPath) == canonicalPath(filepath.Join(path, ".git"))
+}
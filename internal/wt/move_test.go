@@ -0,0 +1,91 @@
+package wt
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMove(t *testing.T) {
+	repo := newTestRepo(t)
+	parent := filepath.Dir(repo)
+	path := filepath.Join(parent, "login")
+	runGit(t, repo, "worktree", "add", "-q", "-b", "feature/login", path, "main")
+	require.NoError(t, WriteMeta(path, Meta{}))
+
+	item, err := Resolve("login")
+	require.NoError(t, err)
+
+	t.Run("moves and updates metadata", func(t *testing.T) {
+		dest := filepath.Join(parent, "archive", "login-old")
+		newPath, err := Move(item, dest, MoveOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, dest, newPath)
+		assert.NoDirExists(t, path)
+		assert.True(t, linksIntact(newPath))
+
+		moved, err := Resolve("feature/login")
+		require.NoError(t, err)
+		assert.Equal(t, newPath, moved.Path)
+
+		meta, err := ReadMeta(newPath)
+		require.NoError(t, err)
+		assert.Equal(t, newPath, meta.Path)
+		item = moved
+	})
+
+	t.Run("moves into an existing directory", func(t *testing.T) {
+		dir := filepath.Join(parent, "trees")
+		require.NoError(t, os.MkdirAll(dir, 0o755))
+		newPath, err := Move(item, dir, MoveOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, filepath.Join(dir, "login-old"), newPath)
+		item.Path = newPath
+	})
+
+	t.Run("refuses the main worktree", func(t *testing.T) {
+		main, err := Resolve("main")
+		require.NoError(t, err)
+		_, err = Move(main, filepath.Join(parent, "elsewhere"), MoveOptions{})
+		assert.ErrorContains(t, err, "main worktree")
+	})
+
+	t.Run("locked worktrees need force", func(t *testing.T) {
+		require.NoError(t, Lock(item, "usb"))
+		item.Locked, item.LockReason = true, "usb"
+		_, err := Move(item, filepath.Join(parent, "locked"), MoveOptions{})
+		var locked *LockedError
+		require.ErrorAs(t, err, &locked)
+
+		newPath, err := Move(item, filepath.Join(parent, "locked"), MoveOptions{Force: true})
+		require.NoError(t, err)
+		assert.DirExists(t, newPath)
+	})
+}
+
+func TestMove_RefusesSubmodules(t *testing.T) {
+	repo := newTestRepo(t)
+	parent := filepath.Dir(repo)
+
+	lib := filepath.Join(parent, "lib")
+	require.NoError(t, os.MkdirAll(lib, 0o755))
+	runGit(t, lib, "init", "-q", "-b", "main")
+	commitFile(t, lib, "lib.txt", "lib")
+
+	path := filepath.Join(parent, "with-sub")
+	runGit(t, repo, "worktree", "add", "-q", "-b", "feature/sub", path, "main")
+	runGit(t, path, "-c", "protocol.file.allow=always", "submodule", "add", "-q", lib, "vendor/lib")
+	runGit(t, path, "commit", "-q", "-m", "Add submodule")
+
+	item, err := Resolve("with-sub")
+	require.NoError(t, err)
+	_, err = Move(item, filepath.Join(parent, "moved"), MoveOptions{})
+	var subErr *SubmoduleError
+	require.ErrorAs(t, err, &subErr)
+	assert.Equal(t, []string{"vendor/lib"}, subErr.Submodules)
+	assert.Contains(t, err.Error(), "submodule deinit")
+	assert.DirExists(t, path)
+}
@@ -0,0 +1,109 @@
+package wt
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// PullRequestRefs are the refs hosting services publish pull/merge request
+// heads under: GitHub first, then GitLab
+var PullRequestRefs = []string{"refs/pull/%d/head", "refs/merge-requests/%d/head"}
+
+// PRBranch is the local branch gitwo checks pull request number out on
+func PRBranch(number int) string {
+	return fmt.Sprintf("pr/%d", number)
+}
+
+// FetchPR looks up pull request number on remote and fetches its head. It
+// returns the ref found and the commit it points to.
+func FetchPR(remote string, number int) (ref, head string, err error) {
+	var refs []string
+	for _, pattern := range PullRequestRefs {
+		refs = append(refs, fmt.Sprintf(pattern, number))
+	}
+	out, err := gitOut(append([]string{"ls-remote", remote}, refs...)...)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to list refs on %s: %w", remote, err)
+	}
+	found := map[string]string{}
+	sc := bufio.NewScanner(bytes.NewReader(out))
+	for sc.Scan() {
+		if fields := strings.Fields(sc.Text()); len(fields) == 2 {
+			found[fields[1]] = fields[0]
+		}
+	}
+	for _, r := range refs {
+		if sha, ok := found[r]; ok {
+			ref, head = r, sha
+			break
+		}
+	}
+	if ref == "" {
+		return "", "", fmt.Errorf("no pull or merge request #%d on %s (looked for %s)", number, remote, strings.Join(refs, ", "))
+	}
+
+	if out, err := gitIn(".", "fetch", "--quiet", "--no-tags", remote, ref); err != nil {
+		return "", "", fmt.Errorf("failed to fetch %s from %s: %s", ref, remote, lastLine(out))
+	}
+	return ref, head, nil
+}
+
+// FindPRWorktree returns the worktree gitwo checked pull request number on
+// remote out in, or nil when there is none
+func FindPRWorktree(remote string, number int) (*WorktreeItem, *Meta, error) {
+	items, err := List()
+	if err != nil {
+		return nil, nil, err
+	}
+	for i, it := range items {
+		if it.Bare || it.Prunable {
+			continue
+		}
+		m, err := ReadMeta(it.Path)
+		if err != nil || m.PR == nil {
+			continue
+		}
+		if m.PR.Number == number && m.PR.Remote == remote {
+			return &items[i], &m, nil
+		}
+	}
+	return nil, nil, nil
+}
+
+// RefreshPR moves the worktree of a pull request to its new head. It only
+// resets clean worktrees whose HEAD is still the commit gitwo checked out,
+// so local changes and commits are never thrown away unless force is set.
+// It reports whether anything changed.
+func RefreshPR(item WorktreeItem, meta Meta, head string, force bool) (bool, error) {
+	current, err := gitIn(item.Path, "rev-parse", "HEAD")
+	if err != nil {
+		return false, fmt.Errorf("cannot read HEAD of %s: %s", item.Path, lastLine(current))
+	}
+	current = strings.TrimSpace(current)
+	if current == head {
+		return false, nil
+	}
+
+	if !force {
+		st := GetStatus(item, "")
+		if st.Err != nil {
+			return false, st.Err
+		}
+		if st.Operation != "" {
+			return false, fmt.Errorf("%s has a %s in progress", item.Path, st.Operation)
+		}
+		if st.Dirty() {
+			return false, fmt.Errorf("%s has uncommitted changes; commit or stash them, or use --force to discard them", item.Path)
+		}
+		if meta.PR != nil && meta.PR.Head != "" && current != meta.PR.Head {
+			return false, fmt.Errorf("%s has commits that are not in the pull request; use --force to reset it anyway", item.Path)
+		}
+	}
+
+	if out, err := gitIn(item.Path, "reset", "--hard", "--quiet", head); err != nil {
+		return false, fmt.Errorf("failed to reset %s: %s", item.Path, lastLine(out))
+	}
+	return true, nil
+}
@@ -0,0 +1,86 @@
+package wt
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFetchPR(t *testing.T) {
+	repo := newTestRepo(t)
+	parent := filepath.Dir(repo)
+	remote := filepath.Join(parent, "remote.git")
+	runGit(t, parent, "init", "-q", "--bare", remote)
+	runGit(t, repo, "remote", "add", "origin", remote)
+
+	commitFile(t, repo, "gh.txt", "github")
+	runGit(t, repo, "push", "-q", "origin", "HEAD:refs/pull/7/head")
+	gh := strings.TrimSpace(runGit(t, repo, "rev-parse", "HEAD"))
+	commitFile(t, repo, "gl.txt", "gitlab")
+	runGit(t, repo, "push", "-q", "origin", "HEAD:refs/merge-requests/8/head")
+	gl := strings.TrimSpace(runGit(t, repo, "rev-parse", "HEAD"))
+	runGit(t, repo, "reset", "-q", "--hard", "HEAD~2")
+
+	ref, head, err := FetchPR("origin", 7)
+	require.NoError(t, err)
+	assert.Equal(t, "refs/pull/7/head", ref)
+	assert.Equal(t, gh, head)
+	runGit(t, repo, "cat-file", "-e", head+"^{commit}")
+
+	ref, head, err = FetchPR("origin", 8)
+	require.NoError(t, err)
+	assert.Equal(t, "refs/merge-requests/8/head", ref)
+	assert.Equal(t, gl, head)
+
+	_, _, err = FetchPR("origin", 9)
+	assert.ErrorContains(t, err, "no pull or merge request #9")
+}
+
+func TestRefreshPR(t *testing.T) {
+	repo := newTestRepo(t)
+	base := strings.TrimSpace(runGit(t, repo, "rev-parse", "HEAD"))
+	commitFile(t, repo, "next.txt", "next")
+	next := strings.TrimSpace(runGit(t, repo, "rev-parse", "HEAD"))
+
+	path := filepath.Join(filepath.Dir(repo), "pr-1")
+	runGit(t, repo, "worktree", "add", "-q", "-b", "pr/1", path, base)
+	meta := Meta{PR: &PRMeta{Number: 1, Remote: "origin", Head: base}}
+	require.NoError(t, WriteMeta(path, meta))
+
+	item, meta2, err := FindPRWorktree("origin", 1)
+	require.NoError(t, err)
+	require.NotNil(t, item)
+	assert.Equal(t, path, item.Path)
+	assert.Equal(t, base, meta2.PR.Head)
+
+	t.Run("refuses dirty worktrees", func(t *testing.T) {
+		writeFile(t, path, "README.md", "changed")
+		_, err := RefreshPR(*item, meta, next, false)
+		assert.ErrorContains(t, err, "uncommitted changes")
+		runGit(t, path, "checkout", "--", "README.md")
+	})
+
+	t.Run("refuses local commits", func(t *testing.T) {
+		commitFile(t, path, "mine.txt", "mine")
+		_, err := RefreshPR(*item, meta, next, false)
+		assert.ErrorContains(t, err, "commits that are not in the pull request")
+		runGit(t, path, "reset", "-q", "--hard", base)
+	})
+
+	t.Run("resets clean worktrees", func(t *testing.T) {
+		changed, err := RefreshPR(*item, meta, next, false)
+		require.NoError(t, err)
+		assert.True(t, changed)
+		assert.Equal(t, next, strings.TrimSpace(runGit(t, path, "rev-parse", "HEAD")))
+		_, err = os.Stat(filepath.Join(path, "next.txt"))
+		assert.NoError(t, err)
+
+		changed, err = RefreshPR(*item, meta, next, false)
+		require.NoError(t, err)
+		assert.False(t, changed)
+	})
+}
@@ -94,10 +94,11 @@ func (pd *ProgressDisplay) RenderStep(step *ProgressStep) {
 	fmt.Printf("• %s%s%s\n", step.Message, statusIcon, details)
 }
 
-// PrintHeader prints the header information
-func PrintHeader(repoPath, repoBranch, repoHead, startPoint, branch, worktreePath string) {
+// PrintHeader prints the header information. requested is the branch name as
+// the user gave it; it is shown when the naming policy changed it.
+func PrintHeader(repoPath, repoBranch, repoHead, startPoint, requested, branch, worktreePath string) {
 	fmt.Printf("gitwo v0.1 • new\n")
-	
+
 	// Safely handle empty repoHead
 	headDisplay := ""
 	if len(repoHead) >= 8 {
@@ -107,10 +108,14 @@ func PrintHeader(repoPath, repoBranch, repoHead, startPoint, branch, worktreePat
 	} else {
 		headDisplay = "unknown"
 	}
-	
+
 	fmt.Printf("Repo        : %s  (%s@%s)\n", repoPath, repoBranch, headDisplay)
 	fmt.Printf("Start point : %s\n", startPoint)
-	fmt.Printf("Branch      : %s   (normalized from \"%s\")\n", branch, branch)
+	if requested != "" && requested != branch {
+		fmt.Printf("Branch      : %s   (normalized from \"%s\")\n", branch, requested)
+	} else {
+		fmt.Printf("Branch      : %s\n", branch)
+	}
 	fmt.Printf("Worktree    : %s\n\n", worktreePath)
 }
 
@@ -92,7 +92,7 @@ func TestPrintHeader(t *testing.T) {
 		r, w, _ := os.Pipe()
 		os.Stdout = w
 		
-		PrintHeader("/path/to/repo", "main", "abc123def456", "HEAD", "feature/test", "../test")
+		PrintHeader("/path/to/repo", "main", "abc123def456", "HEAD", "feature/My Test", "feature/my-test", "../test")
 		
 		w.Close()
 		os.Stdout = originalStdout
@@ -103,7 +103,7 @@ func TestPrintHeader(t *testing.T) {
 		assert.Contains(t, output, "gitwo v0.1 • new")
 		assert.Contains(t, output, "Repo        : /path/to/repo  (main@abc123de)")
 		assert.Contains(t, output, "Start point : HEAD")
-		assert.Contains(t, output, "Branch      : feature/test   (normalized from \"feature/test\")")
+		assert.Contains(t, output, "Branch      : feature/my-test   (normalized from \"feature/My Test\")")
 		assert.Contains(t, output, "Worktree    : ../test")
 	})
 
@@ -114,7 +114,7 @@ func TestPrintHeader(t *testing.T) {
 		r, w, _ := os.Pipe()
 		os.Stdout = w
 		
-		PrintHeader("/path/to/repo", "main", "", "HEAD", "feature/test", "../test")
+		PrintHeader("/path/to/repo", "main", "", "HEAD", "feature/test", "feature/test", "../test")
 		
 		w.Close()
 		os.Stdout = originalStdout
@@ -132,7 +132,7 @@ func TestPrintHeader(t *testing.T) {
 		r, w, _ := os.Pipe()
 		os.Stdout = w
 		
-		PrintHeader("/path/to/repo", "main", "abc", "HEAD", "feature/test", "../test")
+		PrintHeader("/path/to/repo", "main", "abc", "HEAD", "feature/test", "feature/test", "../test")
 		
 		w.Close()
 		os.Stdout = originalStdout
@@ -141,6 +141,8 @@ func TestPrintHeader(t *testing.T) {
 		output := buf.String()
 		
 		assert.Contains(t, output, "Repo        : /path/to/repo  (main@abc)")
+		assert.Contains(t, output, "Branch      : feature/test\n")
+		assert.NotContains(t, output, "normalized from")
 	})
 }
 
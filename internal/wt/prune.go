@@ -0,0 +1,295 @@
+package wt
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Reasons a worktree is offered for pruning
+const (
+	ReasonMerged       = "merged"
+	ReasonRebaseMerged = "rebase-merged"
+	ReasonSquashMerged = "squash-merged"
+	ReasonUpstreamGone = "upstream gone"
+	ReasonStale        = "stale"
+)
+
+// PruneOptions controls which worktrees FindPruneCandidates reports
+type PruneOptions struct {
+	MainBranch string        // ref branches are compared against, e.g. origin/main
+	StaleAfter time.Duration // report branches without commits for this long; 0 disables
+	Now        time.Time     // reference time for staleness; zero means time.Now()
+	Workers    int           // worktrees inspected in parallel
+	// IncludeLocked offers locked worktrees for removal instead of skipping them
+	IncludeLocked bool
+}
+
+// PruneCandidate is a worktree that looks finished
+type PruneCandidate struct {
+	Item    WorktreeItem
+	Reasons []string
+	// Blocked explains why the worktree must not be removed (dirty, unpushed
+	// commits, ...). Empty when it is safe to remove.
+	Blocked string
+}
+
+// Merged reports whether the branch content is known to be in the main branch
+func (c PruneCandidate) Merged() bool {
+	for _, r := range c.Reasons {
+		switch r {
+		case ReasonMerged, ReasonRebaseMerged, ReasonSquashMerged:
+			return true
+		}
+	}
+	return false
+}
+
+// ResolveMainBranch returns the first existing ref among the configured main
+// branch, its local counterpart (origin/main -> main), "main" and "master".
+func ResolveMainBranch(configured string) (string, error) {
+	candidates := []string{configured}
+	if i := strings.Index(configured, "/"); i > 0 {
+		candidates = append(candidates, configured[i+1:])
+	}
+	candidates = append(candidates, "main", "master")
+	for _, ref := range candidates {
+		if ref != "" && gitSilent("rev-parse", "--verify", "--quiet", ref+"^{commit}") == nil {
+			return ref, nil
+		}
+	}
+	return "", fmt.Errorf("main branch %q not found; set main_branch in .gitwo/config.yml", configured)
+}
+
+// FindPruneCandidates lists linked worktrees whose branch is merged into the
+// main branch (including squash and rebase merges), whose upstream is gone,
+// or that have not seen a commit within StaleAfter.
+func FindPruneCandidates(opts PruneOptions) ([]PruneCandidate, error) {
+	items, err := List()
+	if err != nil {
+		return nil, err
+	}
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+	current := CurrentIndex(items)
+	mainLocal := opts.MainBranch
+	if i := strings.Index(mainLocal, "/"); i > 0 && gitSilent("rev-parse", "--verify", "--quiet", "refs/remotes/"+mainLocal) == nil {
+		mainLocal = mainLocal[i+1:]
+	}
+
+	// Skip the main worktree, detached/bare entries and the main branch itself
+	var idx []int
+	for i, it := range items {
+		if i == 0 || it.Bare || it.Branch == "" || it.Branch == mainLocal || it.Branch == opts.MainBranch {
+			continue
+		}
+		idx = append(idx, i)
+	}
+
+	found := make([]*PruneCandidate, len(idx))
+	ForEach(len(idx), opts.Workers, func(n int) {
+		i := idx[n]
+		it := items[i]
+		reasons := pruneReasons(it.Branch, opts)
+		if len(reasons) == 0 {
+			return
+		}
+		c := &PruneCandidate{Item: it, Reasons: reasons}
+		c.Blocked = pruneBlocker(it, i == current, c.Merged(), opts)
+		found[n] = c
+	})
+
+	var candidates []PruneCandidate
+	for _, c := range found {
+		if c != nil {
+			candidates = append(candidates, *c)
+		}
+	}
+	return candidates, nil
+}
+
+func pruneReasons(branch string, opts PruneOptions) []string {
+	var reasons []string
+	if merged := mergedReason(branch, opts.MainBranch); merged != "" {
+		reasons = append(reasons, merged)
+	}
+	if UpstreamGone(branch) {
+		reasons = append(reasons, ReasonUpstreamGone)
+	}
+	if opts.StaleAfter > 0 {
+		if last, err := LastCommitTime(branch); err == nil && opts.Now.Sub(last) > opts.StaleAfter {
+			reasons = append(reasons, fmt.Sprintf("%s (%dd)", ReasonStale, int(opts.Now.Sub(last).Hours()/24)))
+		}
+	}
+	return reasons
+}
+
+// mergedReason detects regular, rebase and squash merges of branch into main
+func mergedReason(branch, main string) string {
+	ref := "refs/heads/" + branch
+
+	if gitSilent("merge-base", "--is-ancestor", ref, main) == nil {
+		// A branch that never got a commit of its own is an ancestor too
+		if neverCommitted(branch) {
+			return ""
+		}
+		return ReasonMerged
+	}
+
+	// Rebase merges: every commit has a patch-equivalent commit in main
+	if allCherryPicked(main, ref) {
+		return ReasonRebaseMerged
+	}
+
+	// Squash merges: the branch's total diff is the diff of a commit in main.
+	// Compared by patch id so no probe commit has to be written.
+	base, err := gitOut("merge-base", main, ref)
+	if err != nil {
+		return ""
+	}
+	diff, err := gitOut("diff", "--no-color", "--no-ext-diff", string(bytesTrimNL(base)), ref)
+	if err != nil || len(diff) == 0 {
+		return ""
+	}
+	squashed := patchIDs(diff)
+	if len(squashed) != 1 {
+		return ""
+	}
+	log, err := gitOut("log", "-p", "--no-merges", "--no-color", "--no-ext-diff", string(bytesTrimNL(base))+".."+main)
+	if err != nil {
+		return ""
+	}
+	if slices.Contains(patchIDs(log), squashed[0]) {
+		return ReasonSquashMerged
+	}
+	return ""
+}
+
+// patchIDs returns the stable patch id of each diff in patch, as
+// `git patch-id` computes them
+func patchIDs(patch []byte) []string {
+	cmd := exec.Command("git", "patch-id", "--stable")
+	cmd.Stdin = bytes.NewReader(patch)
+	out, err := cmd.Output()
+	if err != nil {
+		return nil
+	}
+	var ids []string
+	for _, line := range strings.Split(string(out), "\n") {
+		if f := strings.Fields(line); len(f) > 0 {
+			ids = append(ids, f[0])
+		}
+	}
+	return ids
+}
+
+// allCherryPicked reports whether `git cherry upstream head` lists at least
+// one commit and all of them have an equivalent in upstream
+func allCherryPicked(upstream, head string) bool {
+	out, err := gitOut("cherry", upstream, head)
+	if err != nil {
+		return false
+	}
+	lines := strings.Fields(strings.TrimSpace(string(out)))
+	if len(lines) == 0 {
+		return false
+	}
+	for i := 0; i < len(lines); i += 2 {
+		if lines[i] != "-" {
+			return false
+		}
+	}
+	return true
+}
+
+// neverCommitted reports whether the branch may not have a commit of its
+// own: its reflog only has the creation entry, or there is no reflog to tell
+// (bare repositories, as used by clone and convert, keep none by default)
+func neverCommitted(branch string) bool {
+	out, err := gitOut("reflog", "show", "--format=%H", "refs/heads/"+branch, "--")
+	if err != nil {
+		return true
+	}
+	return len(strings.Fields(string(out))) <= 1
+}
+
+// UpstreamGone reports whether the branch tracks an upstream that no longer exists
+func UpstreamGone(branch string) bool {
+	out, err := gitOut("for-each-ref", "--format=%(upstream:track)", "refs/heads/"+branch)
+	if err != nil {
+		return false
+	}
+	return strings.TrimSpace(string(out)) == "[gone]"
+}
+
+// LastCommitTime returns the committer date of ref
+func LastCommitTime(ref string) (time.Time, error) {
+	out, err := gitOut("log", "-1", "--format=%ct", ref)
+	if err != nil {
+		return time.Time{}, err
+	}
+	sec, err := strconv.ParseInt(strings.TrimSpace(string(out)), 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(sec, 0), nil
+}
+
+// UnpushedCommits counts commits on branch that are neither on any remote nor
+// in the main branch
+func UnpushedCommits(branch, main string) (int, error) {
+	args := []string{"rev-list", "--count", "refs/heads/" + branch, "--not", "--remotes"}
+	if main != "" {
+		args = append(args, main)
+	}
+	out, err := gitOut(args...)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(string(out)))
+}
+
+// pruneBlocker returns why a candidate must be kept, or "" when it is safe
+func pruneBlocker(item WorktreeItem, current, merged bool, opts PruneOptions) string {
+	if current {
+		return "current worktree"
+	}
+	if item.Locked && !opts.IncludeLocked {
+		return item.LockSummary()
+	}
+	st := GetStatus(item, "")
+	if st.Err != nil {
+		if item.Prunable {
+			return "directory missing (run git worktree prune)"
+		}
+		return st.Err.Error()
+	}
+	if st.Operation != "" {
+		return st.Operation + " in progress"
+	}
+	if st.Dirty() {
+		return "uncommitted changes"
+	}
+	if !merged {
+		if n, err := UnpushedCommits(item.Branch, opts.MainBranch); err != nil {
+			return "cannot check unpushed commits"
+		} else if n > 0 {
+			return fmt.Sprintf("%d unpushed commit(s)", n)
+		}
+	}
+	return ""
+}
+
+// DeleteBranch force-deletes a local branch. Callers are expected to have
+// checked that no work is lost.
+func DeleteBranch(branch string) error {
+	if err := gitSilent("branch", "-D", branch); err != nil {
+		return fmt.Errorf("failed to delete branch %s: %w", branch, err)
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package wt
+
+import (
+	"io"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindPruneCandidates(t *testing.T) {
+	repo := newTestRepo(t)
+	parent := filepath.Dir(repo)
+	addWorktree := func(branch string) string {
+		path := filepath.Join(parent, filepath.Base(branch))
+		runGit(t, repo, "worktree", "add", "-q", "-b", branch, path, "main")
+		return path
+	}
+
+	// Regular merge
+	merged := addWorktree("feature/merged")
+	commitFile(t, merged, "merged.txt", "merged")
+	runGit(t, repo, "merge", "-q", "--no-ff", "-m", "Merge feature/merged", "feature/merged")
+
+	// Squash merge of two commits
+	squashed := addWorktree("feature/squashed")
+	commitFile(t, squashed, "a.txt", "a")
+	commitFile(t, squashed, "b.txt", "b")
+	commitFile(t, repo, "main.txt", "main moved on")
+	runGit(t, repo, "merge", "-q", "--squash", "feature/squashed")
+	runGit(t, repo, "commit", "-q", "-m", "Squashed feature")
+
+	// Rebase merge (commits replayed onto main)
+	rebased := addWorktree("feature/rebased")
+	commitFile(t, rebased, "r.txt", "r")
+	commitFile(t, repo, "main2.txt", "main moved on again")
+	runGit(t, repo, "cherry-pick", "feature/rebased")
+
+	// Fresh branch without commits of its own
+	addWorktree("feature/fresh")
+
+	// Unmerged local work
+	wip := addWorktree("feature/wip")
+	commitFile(t, wip, "wip.txt", "wip")
+
+	// Merged but with uncommitted changes
+	dirty := addWorktree("feature/dirty")
+	commitFile(t, dirty, "dirty.txt", "dirty")
+	runGit(t, repo, "merge", "-q", "--no-ff", "-m", "Merge feature/dirty", "feature/dirty")
+	writeFile(t, dirty, "scratch.txt", "not committed")
+
+	// Upstream deleted on the remote
+	remote := filepath.Join(parent, "remote.git")
+	runGit(t, parent, "init", "-q", "--bare", remote)
+	runGit(t, repo, "remote", "add", "origin", remote)
+	addWorktree("feature/gone")
+	runGit(t, repo, "push", "-q", "-u", "origin", "feature/gone")
+	runGit(t, repo, "push", "-q", "origin", "--delete", "feature/gone")
+	runGit(t, repo, "fetch", "-q", "--prune", "origin")
+
+	byBranch := func(candidates []PruneCandidate) map[string]PruneCandidate {
+		m := map[string]PruneCandidate{}
+		for _, c := range candidates {
+			m[c.Item.Branch] = c
+		}
+		return m
+	}
+
+	t.Run("detects merged, squashed, rebased and gone branches", func(t *testing.T) {
+		candidates, err := FindPruneCandidates(PruneOptions{MainBranch: "main", Workers: 4})
+		require.NoError(t, err)
+		got := byBranch(candidates)
+
+		assert.Equal(t, []string{ReasonMerged}, got["feature/merged"].Reasons)
+		assert.Empty(t, got["feature/merged"].Blocked)
+		assert.Equal(t, []string{ReasonSquashMerged}, got["feature/squashed"].Reasons)
+		assert.Empty(t, got["feature/squashed"].Blocked)
+		assert.Equal(t, []string{ReasonRebaseMerged}, got["feature/rebased"].Reasons)
+		assert.Equal(t, []string{ReasonUpstreamGone}, got["feature/gone"].Reasons)
+		assert.Empty(t, got["feature/gone"].Blocked)
+		assert.Equal(t, "uncommitted changes", got["feature/dirty"].Blocked)
+
+		assert.NotContains(t, got, "feature/fresh")
+		assert.NotContains(t, got, "feature/wip")
+		assert.NotContains(t, got, "main")
+	})
+
+	t.Run("stale branches with unpushed commits are blocked", func(t *testing.T) {
+		candidates, err := FindPruneCandidates(PruneOptions{
+			MainBranch: "main",
+			StaleAfter: 30 * 24 * time.Hour,
+			Now:        time.Now().Add(40 * 24 * time.Hour),
+		})
+		require.NoError(t, err)
+		wip, ok := byBranch(candidates)["feature/wip"]
+		require.True(t, ok)
+		assert.Contains(t, wip.Reasons[0], ReasonStale)
+		assert.Equal(t, "1 unpushed commit(s)", wip.Blocked)
+	})
+}
+
+func TestResolveMainBranch(t *testing.T) {
+	newTestRepo(t)
+
+	ref, err := ResolveMainBranch("origin/main")
+	require.NoError(t, err)
+	assert.Equal(t, "main", ref)
+
+	_, err = ResolveMainBranch("origin/trunk")
+	assert.NoError(t, err, "falls back to main")
+}
+
+func TestFindPruneCandidates_BareLayout(t *testing.T) {
+	src := newTestRepo(t)
+	t.Chdir(filepath.Dir(src))
+	res, err := Clone("file://"+src, "shop", io.Discard)
+	require.NoError(t, err)
+	t.Chdir(res.Worktree)
+
+	// Bare repositories keep no branch reflogs, so a fresh branch cannot be
+	// told apart from a merged one that way
+	runGit(t, res.Root, "worktree", "add", "-q", "-b", "feature/fresh", filepath.Join(res.Root, "fresh"), "main")
+
+	candidates, err := FindPruneCandidates(PruneOptions{MainBranch: "main"})
+	require.NoError(t, err)
+	assert.Empty(t, candidates, "a branch without commits of its own is not merged")
+}
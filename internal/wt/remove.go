@@ -3,11 +3,99 @@ package wt
 import (
 	"fmt"
 	"os"
-	"path/filepath"
+	"strconv"
 	"strings"
 )
 
+// RemoveOptions controls the safety checks and cleanup done by RemoveWithOptions
+type RemoveOptions struct {
+	Force        bool // remove even if locked or if uncommitted work or commits would be lost
+	IgnoreLock   bool // remove locked worktrees, but still refuse to lose work
+	DeleteBranch bool // delete the local branch (-d, or -D with Force)
+	DeleteRemote bool // delete the branch's upstream on its remote
+}
+
+// RemovalCheck describes the work that lives only in a worktree
+type RemovalCheck struct {
+	Path   string
+	Branch string
+	Status Status
+	// Unpushed counts commits on Branch that are not on any remote, tag or
+	// other local branch. They are only lost when the branch is deleted.
+	Unpushed int
+}
+
+// CheckRemoval inspects a worktree before it is removed with opts
+func CheckRemoval(item WorktreeItem, opts RemoveOptions) (RemovalCheck, error) {
+	check := RemovalCheck{Path: item.Path, Branch: item.Branch}
+	check.Status = GetStatus(item, "")
+	if check.Status.Err != nil && !item.Prunable {
+		return check, check.Status.Err
+	}
+	if item.Branch != "" {
+		var exclude []string
+		if opts.DeleteRemote {
+			// The upstream is deleted too, so it does not keep the commits
+			if tracking := trackingRefOf(item.Branch); tracking != "" {
+				exclude = append(exclude, tracking)
+			}
+		}
+		n, err := lostCommits(item.Branch, exclude...)
+		if err != nil {
+			return check, err
+		}
+		check.Unpushed = n
+	}
+	return check, nil
+}
+
+// Losses lists what removing the worktree would throw away, one item per line
+func (c RemovalCheck) Losses(deleteBranch bool) []string {
+	var lost []string
+	st := c.Status
+	if st.Operation != "" {
+		lost = append(lost, fmt.Sprintf("%s in progress", st.Operation))
+	}
+	if st.Conflicted > 0 {
+		lost = append(lost, fmt.Sprintf("%d conflicted file(s)", st.Conflicted))
+	}
+	if st.Staged > 0 {
+		lost = append(lost, fmt.Sprintf("%d staged change(s)", st.Staged))
+	}
+	if st.Unstaged > 0 {
+		lost = append(lost, fmt.Sprintf("%d modified file(s)", st.Unstaged))
+	}
+	if st.Untracked > 0 {
+		lost = append(lost, fmt.Sprintf("%d untracked file(s)", st.Untracked))
+	}
+	if deleteBranch && c.Unpushed > 0 {
+		lost = append(lost, fmt.Sprintf("%d commit(s) on %s that are not on any remote", c.Unpushed, c.Branch))
+	}
+	return lost
+}
+
+// UnsafeRemovalError is returned when removing a worktree would lose work
+type UnsafeRemovalError struct {
+	Path   string
+	Losses []string
+}
+
+func (e *UnsafeRemovalError) Error() string {
+	return fmt.Sprintf("refusing to remove %s, it would lose:\n  - %s\nuse --force to remove it anyway",
+		e.Path, strings.Join(e.Losses, "\n  - "))
+}
+
+// Remove removes a worktree after checking that no uncommitted work is lost
 func Remove(worktree string) error {
+	return RemoveWithOptions(worktree, RemoveOptions{})
+}
+
+// RemoveWithOptions removes a worktree and optionally its local and remote
+// branch. Unless opts.Force (or IgnoreLock) is set it refuses locked
+// worktrees with a *LockedError; unless opts.Force is set it returns an *UnsafeRemovalError when uncommitted changes,
+// untracked files or (with DeleteBranch) commits that exist nowhere else
+// would be lost.
+func RemoveWithOptions(worktree string, opts RemoveOptions) error {
 	// Validate input
 	if worktree == "" {
 		return fmt.Errorf("worktree cannot be empty")
@@ -18,23 +106,125 @@ func Remove(worktree string) error {
 		return err
 	}
 
-	// Determine the actual worktree path
-	worktreePath := worktree
-	
-	// If worktree doesn't start with / or ../, assume it's a relative path
-	if !strings.HasPrefix(worktree, "/") && !strings.HasPrefix(worktree, "../") && !strings.HasPrefix(worktree, "./") {
-		worktreePath = filepath.Join("..", worktree)
+	// Find the worktree by path, directory or branch name
+	items, err := List()
+	if err != nil {
+		return err
+	}
+	item, err := ResolveIn(items, worktree)
+	if err != nil {
+		return err
+	}
+	if len(items) > 0 && canonicalPath(item.Path) == canonicalPath(items[0].Path) {
+		return fmt.Errorf("%s is the main worktree and cannot be removed", item.Path)
 	}
+	worktreePath := item.Path
 
 	// Check if worktree exists
 	if _, err := os.Stat(worktreePath); os.IsNotExist(err) {
-		return fmt.Errorf("worktree does not exist: %s", worktreePath)
+		return fmt.Errorf("worktree directory does not exist: %s (run 'git worktree prune' to forget it)", worktreePath)
+	}
+
+	if err := CheckRemovable(item, opts); err != nil {
+		return err
+	}
+
+	branch := item.Branch
+	var remote, remoteRef string
+	if opts.DeleteRemote && branch != "" {
+		// Read the upstream before the branch (and its config) is gone
+		remote, remoteRef = upstreamOf(branch)
 	}
 
 	// Remove the worktree using git worktree remove
-	if err := git("worktree", "remove", worktreePath); err != nil {
+	args := []string{"worktree", "remove"}
+	if opts.Force || opts.IgnoreLock && item.Locked {
+		args = append(args, "--force")
+		if item.Locked {
+			// git needs --force twice to remove a locked worktree
+			args = append(args, "--force")
+		}
+	}
+	if err := git(append(args, worktreePath)...); err != nil {
 		return fmt.Errorf("failed to remove worktree %s: %w", worktreePath, err)
 	}
 
+	if opts.DeleteBranch && branch != "" {
+		flag := "-d"
+		if opts.Force {
+			flag = "-D"
+		}
+		if err := git("branch", flag, branch); err != nil {
+			return fmt.Errorf("worktree removed, but failed to delete branch %s: %w", branch, err)
+		}
+	}
+	if remote != "" {
+		if err := git("push", remote, "--delete", remoteRef); err != nil {
+			return fmt.Errorf("worktree removed, but failed to delete %s on %s: %w", remoteRef, remote, err)
+		}
+	}
 	return nil
 }
+
+// CheckRemovable returns the error RemoveWithOptions would refuse item
+// with: a *LockedError, an *UnsafeRemovalError, or a missing upstream for
+// DeleteRemote. Callers use it to refuse before running pre_remove hooks.
+func CheckRemovable(item WorktreeItem, opts RemoveOptions) error {
+	if item.Locked && !opts.Force && !opts.IgnoreLock {
+		return &LockedError{Path: item.Path, Reason: item.LockReason}
+	}
+	if opts.DeleteRemote && item.Branch != "" {
+		if remote, _ := upstreamOf(item.Branch); remote == "" {
+			return fmt.Errorf("branch %s has no upstream to delete", item.Branch)
+		}
+	}
+	if opts.Force {
+		return nil
+	}
+	check, err := CheckRemoval(item, opts)
+	if err != nil {
+		return err
+	}
+	if losses := check.Losses(opts.DeleteBranch); len(losses) > 0 {
+		return &UnsafeRemovalError{Path: item.Path, Losses: losses}
+	}
+	return nil
+}
+
+// lostCommits counts commits on branch that no remote, tag or other local
+// branch points to. Remote-tracking branches in exclude (e.g.
+// "origin/feature") are not counted as copies.
+func lostCommits(branch string, exclude ...string) (int, error) {
+	args := []string{"rev-list", "--count", "refs/heads/" + branch, "--not", "--exclude=" + branch, "--branches"}
+	for _, e := range exclude {
+		args = append(args, "--exclude="+e)
+	}
+	out, err := gitOut(append(args, "--remotes", "--tags")...)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(string(out)))
+}
+
+// upstreamOf returns the remote name and remote ref a branch tracks
+func upstreamOf(branch string) (remote, ref string) {
+	out, err := gitOut("for-each-ref", "--format=%(upstream:remotename)%00%(upstream:remoteref)", "refs/heads/"+branch)
+	if err != nil {
+		return "", ""
+	}
+	parts := strings.SplitN(strings.TrimSpace(string(out)), "\x00", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", ""
+	}
+	return parts[0], parts[1]
+}
+
+// trackingRefOf returns the remote-tracking branch of a branch's upstream
+// without its refs/remotes/ prefix, e.g. "origin/feature"
+func trackingRefOf(branch string) string {
+	out, err := gitOut("for-each-ref", "--format=%(upstream)", "refs/heads/"+branch)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimPrefix(strings.TrimSpace(string(out)), "refs/remotes/")
+}
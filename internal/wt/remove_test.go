@@ -162,3 +162,69 @@ func TestRemove_Validation(t *testing.T) {
 		})
 	}
 }
+
+func TestRemoveWithOptions(t *testing.T) {
+	repo := newTestRepo(t)
+	parent := filepath.Dir(repo)
+	addWorktree := func(name string) string {
+		path := filepath.Join(parent, name)
+		runGit(t, repo, "worktree", "add", "-q", "-b", "feature/"+name, path, "main")
+		return path
+	}
+	branchExists := func(branch string) bool {
+		return exec.Command("git", "-C", repo, "rev-parse", "--verify", "--quiet", "refs/heads/"+branch).Run() == nil
+	}
+
+	t.Run("refuses to drop uncommitted changes", func(t *testing.T) {
+		path := addWorktree("dirty")
+		writeFile(t, path, "notes.txt", "draft")
+
+		err := RemoveWithOptions(path, RemoveOptions{})
+		var unsafe *UnsafeRemovalError
+		require.ErrorAs(t, err, &unsafe)
+		assert.Equal(t, []string{"1 untracked file(s)"}, unsafe.Losses)
+		assert.DirExists(t, path)
+
+		require.NoError(t, RemoveWithOptions(path, RemoveOptions{Force: true}))
+		assert.NoDirExists(t, path)
+		assert.True(t, branchExists("feature/dirty"), "branch is kept without --delete-branch")
+	})
+
+	t.Run("unpushed commits only block when deleting the branch", func(t *testing.T) {
+		path := addWorktree("local")
+		commitFile(t, path, "local.txt", "local")
+
+		err := RemoveWithOptions(path, RemoveOptions{DeleteBranch: true})
+		var unsafe *UnsafeRemovalError
+		require.ErrorAs(t, err, &unsafe)
+		assert.Equal(t, []string{"1 commit(s) on feature/local that are not on any remote"}, unsafe.Losses)
+
+		require.NoError(t, RemoveWithOptions(path, RemoveOptions{DeleteBranch: true, Force: true}))
+		assert.NoDirExists(t, path)
+		assert.False(t, branchExists("feature/local"))
+	})
+
+	t.Run("deletes local and remote branch", func(t *testing.T) {
+		remote := filepath.Join(parent, "remote.git")
+		runGit(t, parent, "init", "-q", "--bare", remote)
+		runGit(t, repo, "remote", "add", "origin", remote)
+
+		path := addWorktree("pushed")
+		commitFile(t, path, "pushed.txt", "pushed")
+		runGit(t, path, "push", "-q", "-u", "origin", "feature/pushed")
+
+		// The pushed copy is deleted too, so the commit would be lost
+		err := RemoveWithOptions(path, RemoveOptions{DeleteBranch: true, DeleteRemote: true})
+		var unsafe *UnsafeRemovalError
+		require.ErrorAs(t, err, &unsafe)
+		assert.Equal(t, []string{"1 commit(s) on feature/pushed that are not on any remote"}, unsafe.Losses)
+		assert.DirExists(t, path)
+		assert.NotEmpty(t, runGit(t, remote, "branch", "--list", "feature/pushed"))
+
+		require.NoError(t, RemoveWithOptions(path, RemoveOptions{DeleteBranch: true, DeleteRemote: true, Force: true}))
+		assert.NoDirExists(t, path)
+		assert.False(t, branchExists("feature/pushed"))
+		out := runGit(t, remote, "branch", "--list", "feature/pushed")
+		assert.Empty(t, out)
+	})
+}
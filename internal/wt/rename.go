@@ -0,0 +1,58 @@
+package wt
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RenameOptions controls RenameBranch
+type RenameOptions struct {
+	// Remote also renames the upstream branch: the new name is pushed, the
+	// old remote branch deleted and the upstream reset to the new one
+	Remote bool
+}
+
+// RenameResult reports what RenameBranch changed
+type RenameResult struct {
+	Remote       string // remote the branch was renamed on, empty for local only
+	OldRemoteRef string
+	NewRemoteRef string
+}
+
+// RenameBranch renames a local branch, including when it is checked out in a
+// worktree. It refuses to overwrite an existing branch.
+func RenameBranch(oldName, newName string, opts RenameOptions) (RenameResult, error) {
+	var res RenameResult
+	if err := gitSilent("check-ref-format", "--branch", newName); err != nil {
+		return res, fmt.Errorf("%q is not a valid branch name", newName)
+	}
+	if gitSilent("show-ref", "--verify", "--quiet", "refs/heads/"+newName) == nil {
+		return res, fmt.Errorf("branch %s already exists", newName)
+	}
+
+	var remote, oldRef string
+	if opts.Remote {
+		// Read the upstream before the rename moves the branch config
+		remote, oldRef = upstreamOf(oldName)
+		if remote == "" {
+			return res, fmt.Errorf("branch %s has no upstream to rename", oldName)
+		}
+	}
+
+	if out, err := gitIn(".", "branch", "-m", oldName, newName); err != nil {
+		return res, fmt.Errorf("failed to rename branch %s: %s", oldName, strings.TrimSpace(out))
+	}
+	if remote == "" {
+		return res, nil
+	}
+
+	// Push the new name first so a failure never leaves the remote without the branch
+	res.Remote, res.OldRemoteRef, res.NewRemoteRef = remote, strings.TrimPrefix(oldRef, "refs/heads/"), newName
+	if out, err := gitIn(".", "push", "--quiet", "--set-upstream", remote, "refs/heads/"+newName+":refs/heads/"+newName); err != nil {
+		return res, fmt.Errorf("renamed %s to %s locally, but pushing to %s failed: %s", oldName, newName, remote, lastLine(out))
+	}
+	if out, err := gitIn(".", "push", "--quiet", remote, "--delete", oldRef); err != nil {
+		return res, fmt.Errorf("pushed %s to %s, but deleting %s there failed: %s", newName, remote, res.OldRemoteRef, lastLine(out))
+	}
+	return res, nil
+}
@@ -0,0 +1,48 @@
+package wt
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenameBranch(t *testing.T) {
+	repo := newTestRepo(t)
+	parent := filepath.Dir(repo)
+	remote := filepath.Join(parent, "remote.git")
+	runGit(t, parent, "init", "-q", "--bare", remote)
+	runGit(t, repo, "remote", "add", "origin", remote)
+
+	path := filepath.Join(parent, "foo")
+	runGit(t, repo, "worktree", "add", "-q", "-b", "feature/foo", path, "main")
+	commitFile(t, path, "foo.txt", "foo")
+	runGit(t, path, "push", "-q", "-u", "origin", "feature/foo")
+
+	t.Run("refuses invalid and existing names", func(t *testing.T) {
+		_, err := RenameBranch("feature/foo", "feature/..bad", RenameOptions{})
+		assert.ErrorContains(t, err, "not a valid branch name")
+		_, err = RenameBranch("feature/foo", "main", RenameOptions{})
+		assert.ErrorContains(t, err, "already exists")
+	})
+
+	t.Run("renames local and remote branch", func(t *testing.T) {
+		res, err := RenameBranch("feature/foo", "feature/bar", RenameOptions{Remote: true})
+		require.NoError(t, err)
+		assert.Equal(t, RenameResult{Remote: "origin", OldRemoteRef: "feature/foo", NewRemoteRef: "feature/bar"}, res)
+
+		assert.Equal(t, "feature/bar", strings.TrimSpace(runGit(t, path, "branch", "--show-current")))
+		assert.Equal(t, "origin/feature/bar", strings.TrimSpace(runGit(t, path, "rev-parse", "--abbrev-ref", "@{upstream}")))
+		remoteBranches := runGit(t, remote, "branch", "--format=%(refname:short)")
+		assert.Equal(t, []string{"feature/bar"}, strings.Fields(remoteBranches))
+	})
+
+	t.Run("remote rename needs an upstream", func(t *testing.T) {
+		runGit(t, repo, "branch", "feature/local")
+		_, err := RenameBranch("feature/local", "feature/other", RenameOptions{Remote: true})
+		assert.ErrorContains(t, err, "no upstream")
+		assert.NotEmpty(t, runGit(t, repo, "branch", "--list", "feature/local"), "nothing is renamed when the remote part cannot be done")
+	})
+}
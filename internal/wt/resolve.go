@@ -0,0 +1,211 @@
+package wt
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// AmbiguousError is returned when a query matches more than one worktree
+type AmbiguousError struct {
+	Query      string
+	Candidates []WorktreeItem
+}
+
+func (e *AmbiguousError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%q matches %d worktrees:", e.Query, len(e.Candidates))
+	for _, c := range e.Candidates {
+		fmt.Fprintf(&b, "\n  %s (%s)", c.Path, c.Label())
+	}
+	return b.String()
+}
+
+// NotFoundError is returned when no worktree matches a query
+type NotFoundError struct {
+	Query       string
+	Suggestions []string
+}
+
+func (e *NotFoundError) Error() string {
+	msg := fmt.Sprintf("no worktree matches %q", e.Query)
+	if len(e.Suggestions) > 0 {
+		msg += fmt.Sprintf("\ndid you mean: %s?", strings.Join(e.Suggestions, ", "))
+	}
+	return msg + "\nrun 'gitwo list' to see all worktrees"
+}
+
+// Label is the branch name, or "detached" for worktrees without a branch
+func (w WorktreeItem) Label() string {
+	if w.Branch != "" {
+		return w.Branch
+	}
+	if w.Bare {
+		return "bare"
+	}
+	if w.Ref != "" {
+		return "detached at " + w.Ref
+	}
+	return "detached"
+}
+
+// Resolve finds the worktree a user means. See ResolveIn for the rules.
+func Resolve(query string) (WorktreeItem, error) {
+	items, err := List()
+	if err != nil {
+		return WorktreeItem{}, err
+	}
+	return ResolveIn(items, query)
+}
+
+// ResolveIn finds the worktree meant by query among items. A query may be
+//   - a path to the worktree or to a directory inside it,
+//   - the worktree directory's basename,
+//   - the full branch name (feature/login),
+//   - the branch name without its prefix (login), or
+//   - a unique prefix of any of the above names.
+//
+// Exact name matches win over prefix matches. Several matches on the same
+// level yield an *AmbiguousError, no match a *NotFoundError with suggestions.
+func ResolveIn(items []WorktreeItem, query string) (WorktreeItem, error) {
+	if query == "" {
+		return WorktreeItem{}, fmt.Errorf("worktree cannot be empty")
+	}
+
+	explicitPath := filepath.IsAbs(query) || query == "." || query == ".." ||
+		strings.HasPrefix(query, "./") || strings.HasPrefix(query, "../")
+	if explicitPath {
+		if it, ok := matchPath(items, query); ok {
+			return it, nil
+		}
+		return WorktreeItem{}, &NotFoundError{Query: query}
+	}
+
+	for _, match := range []func(name string) bool{
+		func(name string) bool { return name == query },
+		func(name string) bool { return strings.HasPrefix(name, query) },
+	} {
+		var found []WorktreeItem
+		for _, it := range items {
+			if it.Bare {
+				continue
+			}
+			for _, name := range worktreeNames(it) {
+				if match(name) {
+					found = append(found, it)
+					break
+				}
+			}
+		}
+		switch len(found) {
+		case 0:
+			continue
+		case 1:
+			return found[0], nil
+		default:
+			return WorktreeItem{}, &AmbiguousError{Query: query, Candidates: found}
+		}
+	}
+
+	// Other relative paths, e.g. "trees/login" or a directory inside a worktree
+	if strings.ContainsRune(query, filepath.Separator) {
+		if it, ok := matchPath(items, query); ok {
+			return it, nil
+		}
+	}
+	return WorktreeItem{}, &NotFoundError{Query: query, Suggestions: suggest(items, query)}
+}
+
+// matchPath returns the worktree that is or contains path (deepest wins, so
+// nested worktrees resolve to the inner one)
+func matchPath(items []WorktreeItem, path string) (WorktreeItem, bool) {
+	target := canonicalPath(path)
+	best, bestLen := -1, -1
+	for i, it := range items {
+		root := canonicalPath(it.Path)
+		rel, err := filepath.Rel(root, target)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		if len(root) > bestLen {
+			best, bestLen = i, len(root)
+		}
+	}
+	if best < 0 {
+		return WorktreeItem{}, false
+	}
+	return items[best], true
+}
+
+// worktreeNames lists the names a worktree can be addressed by
+func worktreeNames(it WorktreeItem) []string {
+	names := []string{filepath.Base(it.Path)}
+	if it.Branch != "" {
+		names = append(names, it.Branch)
+		if i := strings.Index(it.Branch, "/"); i >= 0 {
+			names = append(names, it.Branch[i+1:])
+		}
+		if i := strings.LastIndex(it.Branch, "/"); i >= 0 {
+			names = append(names, it.Branch[i+1:])
+		}
+	}
+	return names
+}
+
+// suggest returns up to three names close to query, closest first
+func suggest(items []WorktreeItem, query string) []string {
+	type scored struct {
+		name string
+		dist int
+	}
+	var found []scored
+	seen := map[string]bool{}
+	limit := len(query)/3 + 1
+	if limit < 2 {
+		limit = 2
+	}
+	for _, it := range items {
+		if it.Bare {
+			continue
+		}
+		for _, name := range worktreeNames(it) {
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+			if d := levenshtein(strings.ToLower(query), strings.ToLower(name)); d <= limit {
+				found = append(found, scored{name, d})
+			}
+		}
+	}
+	sort.SliceStable(found, func(i, j int) bool { return found[i].dist < found[j].dist })
+
+	var out []string
+	for i := 0; i < len(found) && i < 3; i++ {
+		out = append(out, found[i].name)
+	}
+	return out
+}
+
+// levenshtein computes the edit distance between a and b
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
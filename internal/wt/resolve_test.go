@@ -0,0 +1,85 @@
+package wt
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveIn(t *testing.T) {
+	root := t.TempDir()
+	items := []WorktreeItem{
+		{Path: filepath.Join(root, "shop"), Branch: "main"},
+		{Path: filepath.Join(root, "shop-feature-login"), Branch: "feature/login"},
+		{Path: filepath.Join(root, "shop-feature-logout"), Branch: "feature/logout"},
+		{Path: filepath.Join(root, "trees", "payments"), Branch: "bugfix/payments"},
+		{Path: filepath.Join(root, "review"), Detached: true},
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{"absolute path", filepath.Join(root, "review"), "review"},
+		{"path inside a worktree", filepath.Join(root, "trees", "payments", "src"), "payments"},
+		{"directory basename", "shop-feature-login", "shop-feature-login"},
+		{"full branch", "feature/logout", "shop-feature-logout"},
+		{"branch without prefix", "payments", "payments"},
+		{"exact match beats prefix", "login", "shop-feature-login"},
+		{"unique prefix", "pay", "payments"},
+		{"unique branch prefix", "bugfix/p", "payments"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ResolveIn(items, tt.query)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, filepath.Base(got.Path))
+		})
+	}
+
+	t.Run("ambiguous prefix lists candidates", func(t *testing.T) {
+		_, err := ResolveIn(items, "log")
+		var ambiguous *AmbiguousError
+		require.ErrorAs(t, err, &ambiguous)
+		assert.Len(t, ambiguous.Candidates, 2)
+		assert.Contains(t, err.Error(), "feature/login")
+		assert.Contains(t, err.Error(), "feature/logout")
+	})
+
+	t.Run("typo suggests close names", func(t *testing.T) {
+		_, err := ResolveIn(items, "paymnets")
+		var notFound *NotFoundError
+		require.ErrorAs(t, err, &notFound)
+		assert.Equal(t, []string{"payments"}, notFound.Suggestions)
+		assert.Contains(t, err.Error(), "did you mean: payments?")
+	})
+
+	t.Run("unknown path", func(t *testing.T) {
+		_, err := ResolveIn(items, "/nowhere/else")
+		var notFound *NotFoundError
+		assert.ErrorAs(t, err, &notFound)
+	})
+}
+
+func TestFuzzyFilter(t *testing.T) {
+	items := []WorktreeItem{
+		{Path: "/w/shop", Branch: "main"},
+		{Path: "/w/shop-feature-login", Branch: "feature/login"},
+		{Path: "/w/shop-bugfix-payments", Branch: "bugfix/payments"},
+		{Path: "/w/bare", Bare: true},
+	}
+
+	got := FuzzyFilter(items, "fl")
+	require.NotEmpty(t, got)
+	assert.Equal(t, "feature/login", got[0].Branch)
+
+	got = FuzzyFilter(items, "pmt")
+	require.Len(t, got, 1)
+	assert.Equal(t, "bugfix/payments", got[0].Branch)
+
+	assert.Len(t, FuzzyFilter(items, ""), 3, "empty query returns everything but bare entries")
+	assert.Empty(t, FuzzyFilter(items, "xyz"))
+}
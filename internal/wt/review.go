@@ -0,0 +1,191 @@
+package wt
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gitwohq/gitwo/internal/pathtmpl"
+)
+
+// EphemeralDir is where review worktrees live, relative to the main root
+const EphemeralDir = ".gitwo/tmp"
+
+// EphemeralMeta marks a temporary worktree that gitwo removes once it expires
+type EphemeralMeta struct {
+	Ref     string    `json:"ref"`
+	Head    string    `json:"head"` // commit the worktree was created at
+	Expires time.Time `json:"expires"`
+}
+
+// Expired reports whether the worktree may be cleaned up at now
+func (e *EphemeralMeta) Expired(now time.Time) bool {
+	return e != nil && !now.Before(e.Expires)
+}
+
+// ParseTTL parses a time to live such as "2d", "1w", "12h" or "90m". Plain
+// Go durations ("1h30m") work too.
+func ParseTTL(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	units := map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour}
+	for suffix, unit := range units {
+		if n, ok := strings.CutSuffix(s, suffix); ok {
+			v, err := strconv.Atoi(n)
+			if err != nil || v <= 0 {
+				return 0, fmt.Errorf("invalid ttl %q, e.g. 2d, 12h or 90m", s)
+			}
+			return time.Duration(v) * unit, nil
+		}
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid ttl %q, e.g. 2d, 12h or 90m", s)
+	}
+	return d, nil
+}
+
+// Review is a temporary worktree created by CreateReview
+type Review struct {
+	Path    string
+	Ref     string
+	Head    string
+	Base    string // merge-base of Head and the base ref, "" when there is none
+	Expires time.Time
+}
+
+// CreateReview checks ref out detached in a new worktree under EphemeralDir,
+// named <ref>-<id>, that expires after ttl. base is the ref the merge-base is
+// computed against.
+func CreateReview(ref, base string, ttl time.Duration) (*Review, error) {
+	root, err := MainRoot()
+	if err != nil {
+		return nil, err
+	}
+	out, err := gitOut("rev-parse", "--verify", "--quiet", ref+"^{commit}")
+	if err != nil {
+		return nil, fmt.Errorf("unknown ref %q", ref)
+	}
+	r := &Review{Ref: ref, Head: string(bytesTrimNL(out)), Expires: time.Now().Add(ttl)}
+	if out, err := gitOut("merge-base", base, r.Head); err == nil {
+		r.Base = string(bytesTrimNL(out))
+	}
+
+	path, err := addEphemeral(root, pathtmpl.Sanitize(ref), &EphemeralMeta{Ref: ref, Head: r.Head, Expires: r.Expires})
+	if err != nil {
+		return nil, err
+	}
+	r.Path = path
+	return r, nil
+}
+
+// addEphemeral creates a detached worktree at eph.Head under EphemeralDir,
+// named <name>-<id>, and records eph in its metadata
+func addEphemeral(root, name string, eph *EphemeralMeta) (string, error) {
+	dir := filepath.Join(root, EphemeralDir)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	// Keep the temporary worktrees out of the main worktree's git status
+	ignore := filepath.Join(dir, ".gitignore")
+	if _, err := os.Stat(ignore); os.IsNotExist(err) {
+		_ = os.WriteFile(ignore, []byte("*\n"), 0o644)
+	}
+
+	id := make([]byte, 3)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, name+"-"+hex.EncodeToString(id))
+	if out, err := gitIn(root, "worktree", "add", "--quiet", "--detach", path, eph.Head); err != nil {
+		return "", fmt.Errorf("git worktree add failed: %s", lastLine(out))
+	}
+	if err := WriteMeta(path, Meta{Created: time.Now(), Ephemeral: eph}); err != nil {
+		_, _ = gitIn(root, "worktree", "remove", "--force", path)
+		return "", fmt.Errorf("failed to record the expiry: %w", err)
+	}
+	return path, nil
+}
+
+// ExpiredWorktree is an ephemeral worktree found by CleanExpired
+type ExpiredWorktree struct {
+	Item    WorktreeItem
+	Meta    Meta
+	Removed bool
+	// Kept explains why an expired worktree was not removed, e.g. its
+	// uncommitted changes
+	Kept string
+}
+
+// CleanExpired removes the ephemeral worktrees that expired before now and
+// have nothing worth keeping. Dirty ones, ones with commits that are on no
+// branch and the one holding the current directory are kept and reported.
+func CleanExpired(now time.Time) ([]ExpiredWorktree, error) {
+	items, err := List()
+	if err != nil {
+		return nil, err
+	}
+	var expired []ExpiredWorktree
+	for _, it := range items {
+		if it.Bare || it.Prunable {
+			continue
+		}
+		meta, err := ReadMeta(it.Path)
+		if err != nil || !meta.Ephemeral.Expired(now) {
+			continue
+		}
+		e := ExpiredWorktree{Item: it, Meta: meta}
+		switch {
+		case cwdWithin(it.Path):
+			e.Kept = "in use"
+		case it.Locked:
+			e.Kept = "locked"
+		default:
+			e.Kept = ephemeralLosses(it, meta.Ephemeral)
+		}
+		if e.Kept == "" {
+			if out, err := gitIn(".", "worktree", "remove", it.Path); err != nil {
+				e.Kept = lastLine(out)
+			} else {
+				e.Removed = true
+			}
+		}
+		expired = append(expired, e)
+	}
+	return expired, nil
+}
+
+// ephemeralLosses describes what removing a temporary worktree would throw
+// away, or "" when it is safe to remove. Commits made on top of the reviewed
+// commit count unless a branch, tag or remote has them.
+func ephemeralLosses(item WorktreeItem, eph *EphemeralMeta) string {
+	check, err := CheckRemoval(item, RemoveOptions{})
+	if err != nil {
+		return err.Error()
+	}
+	lost := check.Losses(false)
+	args := []string{"-C", item.Path, "rev-list", "--count", "HEAD", "--not", "--branches", "--remotes", "--tags"}
+	if eph.Head != "" {
+		args = append(args, eph.Head)
+	}
+	if out, err := gitOut(args...); err == nil {
+		if n := strings.TrimSpace(string(out)); n != "0" {
+			lost = append(lost, n+" commit(s) on no branch")
+		}
+	}
+	return strings.Join(lost, ", ")
+}
+
+// cwdWithin reports whether the current directory is path or below it
+func cwdWithin(path string) bool {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return false
+	}
+	rel, err := filepath.Rel(canonicalPath(path), canonicalPath(cwd))
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
@@ -0,0 +1,109 @@
+package wt
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTTL(t *testing.T) {
+	valid := map[string]time.Duration{
+		"2d":    48 * time.Hour,
+		"1w":    7 * 24 * time.Hour,
+		"12h":   12 * time.Hour,
+		"90m":   90 * time.Minute,
+		"1h30m": 90 * time.Minute,
+	}
+	for in, want := range valid {
+		got, err := ParseTTL(in)
+		require.NoError(t, err, in)
+		assert.Equal(t, want, got, in)
+	}
+	for _, in := range []string{"", "d", "-1d", "0h", "soon"} {
+		_, err := ParseTTL(in)
+		assert.Error(t, err, in)
+	}
+}
+
+func TestCreateReview(t *testing.T) {
+	repo := newTestRepo(t)
+	runGit(t, repo, "checkout", "-q", "-b", "feature/login")
+	commitFile(t, repo, "login.go", "package login\n")
+	runGit(t, repo, "checkout", "-q", "main")
+
+	r, err := CreateReview("feature/login", "main", 2*time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(repo, ".gitwo", "tmp"), filepath.Dir(r.Path))
+	assert.True(t, strings.HasPrefix(filepath.Base(r.Path), "feature-login-"))
+	assert.Equal(t, strings.TrimSpace(runGit(t, repo, "rev-parse", "main")), r.Base)
+	assert.FileExists(t, filepath.Join(r.Path, "login.go"))
+
+	item, err := FindByPath(r.Path)
+	require.NoError(t, err)
+	require.NotNil(t, item)
+	assert.True(t, item.Detached)
+
+	meta, err := ReadMeta(r.Path)
+	require.NoError(t, err)
+	require.NotNil(t, meta.Ephemeral)
+	assert.Equal(t, "feature/login", meta.Ephemeral.Ref)
+	assert.WithinDuration(t, time.Now().Add(2*time.Hour), meta.Ephemeral.Expires, time.Minute)
+
+	// The main worktree does not see the temporary worktrees
+	assert.Empty(t, runGit(t, repo, "status", "--porcelain", "--", ".gitwo/tmp"))
+
+	_, err = CreateReview("no-such-ref", "main", time.Hour)
+	assert.Error(t, err)
+}
+
+func TestCleanExpired(t *testing.T) {
+	repo := newTestRepo(t)
+	review := func(ttl time.Duration) string {
+		r, err := CreateReview("main", "main", time.Hour)
+		require.NoError(t, err)
+		require.NoError(t, UpdateMeta(r.Path, func(m *Meta) { m.Ephemeral.Expires = time.Now().Add(ttl) }))
+		return r.Path
+	}
+	clean := review(-time.Minute)
+	dirty := review(-time.Minute)
+	writeFile(t, dirty, "scratch.txt", "notes\n")
+	committed := review(-time.Minute)
+	commitFile(t, committed, "fix.txt", "fix\n")
+	active := review(time.Hour)
+
+	expired, err := CleanExpired(time.Now())
+	require.NoError(t, err)
+	require.Len(t, expired, 3)
+
+	kept := map[string]string{}
+	for _, e := range expired {
+		if e.Removed {
+			assert.Equal(t, clean, e.Item.Path)
+		} else {
+			kept[e.Item.Path] = e.Kept
+		}
+	}
+	assert.NoDirExists(t, clean)
+	assert.Contains(t, kept[dirty], "1 untracked file(s)")
+	assert.Contains(t, kept[committed], "1 commit(s) on no branch")
+	assert.DirExists(t, active)
+
+	t.Chdir(dirty)
+	require.NoError(t, os.Remove(filepath.Join(dirty, "scratch.txt")))
+	expired, err = CleanExpired(time.Now())
+	require.NoError(t, err)
+	for _, e := range expired {
+		if e.Item.Path == dirty {
+			assert.Equal(t, "in use", e.Kept)
+		}
+	}
+	t.Chdir(repo)
+	_, err = CleanExpired(time.Now())
+	require.NoError(t, err)
+	assert.NoDirExists(t, dirty)
+}
@@ -0,0 +1,78 @@
+package wt
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SparseCheckout turns on cone-mode sparse-checkout for the worktree at path
+// (only that worktree; git keeps the setting in its worktree config) and
+// checks out dirs. It is meant for worktrees added with --no-checkout.
+func SparseCheckout(path string, dirs []string) error {
+	if err := SparseSet(path, dirs); err != nil {
+		return err
+	}
+	if out, err := gitIn(path, "checkout"); err != nil {
+		return fmt.Errorf("checkout in %s failed: %s", path, lastLine(out))
+	}
+	return nil
+}
+
+// SparseSet replaces the sparse-checkout directories of a worktree, enabling
+// cone mode if it is not sparse yet
+func SparseSet(path string, dirs []string) error {
+	if len(dirs) == 0 {
+		return fmt.Errorf("no sparse-checkout directories given")
+	}
+	args := append([]string{"sparse-checkout", "set", "--cone", "--"}, dirs...)
+	if out, err := gitIn(path, args...); err != nil {
+		return fmt.Errorf("git sparse-checkout set failed: %s", lastLine(out))
+	}
+	return nil
+}
+
+// SparseAdd adds directories to a sparse worktree
+func SparseAdd(path string, dirs []string) error {
+	if len(dirs) == 0 {
+		return fmt.Errorf("no sparse-checkout directories given")
+	}
+	if !IsSparse(path) {
+		return fmt.Errorf("%s is not a sparse worktree; use 'set' to make it one", path)
+	}
+	args := append([]string{"sparse-checkout", "add", "--"}, dirs...)
+	if out, err := gitIn(path, args...); err != nil {
+		return fmt.Errorf("git sparse-checkout add failed: %s", lastLine(out))
+	}
+	return nil
+}
+
+// SparseDisable checks out the full tree again
+func SparseDisable(path string) error {
+	if out, err := gitIn(path, "sparse-checkout", "disable"); err != nil {
+		return fmt.Errorf("git sparse-checkout disable failed: %s", lastLine(out))
+	}
+	return nil
+}
+
+// IsSparse reports whether sparse-checkout is enabled in the worktree
+func IsSparse(path string) bool {
+	out, err := gitIn(path, "config", "--bool", "core.sparseCheckout")
+	return err == nil && strings.TrimSpace(out) == "true"
+}
+
+// SparseDirs lists the sparse-checkout directories of a worktree, or nil
+// when it has a full checkout
+func SparseDirs(path string) ([]string, error) {
+	if !IsSparse(path) {
+		return nil, nil
+	}
+	out, err := gitOut("-C", path, "sparse-checkout", "list")
+	if err != nil {
+		return nil, fmt.Errorf("git sparse-checkout list failed in %s: %w", path, err)
+	}
+	dirs := strings.Split(strings.TrimSpace(string(out)), "\n")
+	if len(dirs) == 1 && dirs[0] == "" {
+		return []string{}, nil
+	}
+	return dirs, nil
+}
@@ -0,0 +1,49 @@
+package wt
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSparseCheckout(t *testing.T) {
+	repo := newTestRepo(t)
+	for _, f := range []string{"services/api/main.go", "libs/common/lib.go", "web/index.html"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(repo, filepath.Dir(f)), 0o755))
+		commitFile(t, repo, f, f)
+	}
+
+	path := filepath.Join(filepath.Dir(repo), "api")
+	runGit(t, repo, "worktree", "add", "-q", "--no-checkout", "-b", "feature/api", path, "main")
+	require.NoError(t, SparseCheckout(path, []string{"services/api"}))
+
+	assert.FileExists(t, filepath.Join(path, "services", "api", "main.go"))
+	assert.FileExists(t, filepath.Join(path, "README.md"), "cone mode keeps top-level files")
+	assert.NoDirExists(t, filepath.Join(path, "web"))
+	assert.False(t, IsSparse(repo), "only the new worktree is sparse")
+
+	st := GetStatus(WorktreeItem{Path: path, Branch: "feature/api"}, "")
+	assert.False(t, st.Dirty())
+	assert.Equal(t, []string{"services/api"}, st.Sparse)
+
+	require.NoError(t, SparseAdd(path, []string{"libs/common"}))
+	dirs, err := SparseDirs(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"libs/common", "services/api"}, dirs)
+	assert.DirExists(t, filepath.Join(path, "libs", "common"))
+
+	require.NoError(t, SparseSet(path, []string{"web"}))
+	assert.DirExists(t, filepath.Join(path, "web"))
+	assert.NoDirExists(t, filepath.Join(path, "services"))
+
+	require.NoError(t, SparseDisable(path))
+	dirs, err = SparseDirs(path)
+	require.NoError(t, err)
+	assert.Nil(t, dirs)
+	assert.DirExists(t, filepath.Join(path, "services", "api"))
+
+	assert.ErrorContains(t, SparseAdd(path, []string{"web"}), "not a sparse worktree")
+}
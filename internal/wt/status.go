@@ -0,0 +1,295 @@
+package wt
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Status describes the state of a single worktree
+type Status struct {
+	Staged     int
+	Unstaged   int
+	Untracked  int
+	Conflicted int
+
+	// Ahead/behind against the branch's upstream
+	Upstream string
+	Ahead    int
+	Behind   int
+
+	// Ahead/behind against main_branch
+	Main       string
+	MainAhead  int
+	MainBehind int
+
+	// Operation in progress: rebase, merge, cherry-pick, revert, bisect or ""
+	Operation string
+
+	Locked   bool
+	Prunable bool
+	Bare     bool // bare repositories have no work tree to inspect
+
+	// Sparse lists the sparse-checkout directories; nil for a full checkout
+	Sparse []string
+
+	// DriftedSubmodules lists submodules checked out at a commit other than
+	// the recorded one
+	DriftedSubmodules []string
+
+	// Err is set when the worktree could not be inspected (e.g. missing directory)
+	Err error
+}
+
+// Dirty reports whether the worktree has local changes of any kind
+func (s Status) Dirty() bool {
+	return s.Staged+s.Unstaged+s.Untracked+s.Conflicted > 0
+}
+
+// String renders a compact summary such as "2 staged, 1 modified, REBASING"
+func (s Status) String() string {
+	if s.Bare {
+		return "BARE"
+	}
+	if s.Err != nil {
+		if s.Prunable {
+			return "MISSING (prunable)"
+		}
+		return "ERROR"
+	}
+
+	var parts []string
+	if s.Conflicted > 0 {
+		parts = append(parts, fmt.Sprintf("%d conflicted", s.Conflicted))
+	}
+	if s.Staged > 0 {
+		parts = append(parts, fmt.Sprintf("%d staged", s.Staged))
+	}
+	if s.Unstaged > 0 {
+		parts = append(parts, fmt.Sprintf("%d modified", s.Unstaged))
+	}
+	if s.Untracked > 0 {
+		parts = append(parts, fmt.Sprintf("%d untracked", s.Untracked))
+	}
+	if len(parts) == 0 {
+		parts = append(parts, "CLEAN")
+	}
+	if s.Operation != "" {
+		parts = append(parts, operationLabel(s.Operation))
+	}
+	if s.Prunable {
+		parts = append(parts, "prunable")
+	}
+	return strings.Join(parts, ", ")
+}
+
+// UpstreamSummary renders the upstream comparison, e.g. "origin/x ↑1 ↓2"
+func (s Status) UpstreamSummary() string {
+	if s.Upstream == "" {
+		return "-"
+	}
+	return fmt.Sprintf("%s ↑%d ↓%d", s.Upstream, s.Ahead, s.Behind)
+}
+
+// SparseSummary renders the sparse-checkout directories, or "-" for a full checkout
+func (s Status) SparseSummary() string {
+	if s.Sparse == nil {
+		return "-"
+	}
+	if len(s.Sparse) == 0 {
+		return "sparse (root only)"
+	}
+	return strings.Join(s.Sparse, ",")
+}
+
+// SubmoduleSummary renders the drifted submodules, or "-" when there are none
+func (s Status) SubmoduleSummary() string {
+	if len(s.DriftedSubmodules) == 0 {
+		return "-"
+	}
+	return "drifted: " + strings.Join(s.DriftedSubmodules, ",")
+}
+
+// MainSummary renders the comparison with main_branch, e.g. "+3 -1"
+func (s Status) MainSummary() string {
+	if s.Main == "" {
+		return "-"
+	}
+	return fmt.Sprintf("+%d -%d", s.MainAhead, s.MainBehind)
+}
+
+func operationLabel(op string) string {
+	switch op {
+	case "rebase":
+		return "REBASING"
+	case "merge":
+		return "MERGING"
+	case "cherry-pick":
+		return "CHERRY-PICKING"
+	case "revert":
+		return "REVERTING"
+	case "bisect":
+		return "BISECTING"
+	default:
+		return strings.ToUpper(op)
+	}
+}
+
+// GetStatus inspects a worktree. mainBranch may be empty or point to a ref
+// that does not exist, in which case the main comparison is skipped.
+func GetStatus(item WorktreeItem, mainBranch string) Status {
+	st := Status{Locked: item.Locked, Prunable: item.Prunable, Bare: item.Bare}
+	if item.Bare {
+		return st
+	}
+
+	if _, err := os.Stat(item.Path); err != nil {
+		st.Err = fmt.Errorf("worktree directory is missing: %s", item.Path)
+		return st
+	}
+
+	out, err := gitOut("-C", item.Path, "status", "--porcelain=v2", "--branch")
+	if err != nil {
+		st.Err = fmt.Errorf("git status failed in %s: %w", item.Path, err)
+		return st
+	}
+	parseStatusV2(out, &st)
+
+	st.Operation = operationInProgress(item.Path)
+	st.Sparse, _ = SparseDirs(item.Path)
+	st.DriftedSubmodules = DriftedSubmodules(item.Path)
+
+	if mainBranch != "" && item.Branch != "" && gitSilent("-C", item.Path, "rev-parse", "--verify", "--quiet", mainBranch) == nil {
+		if ahead, behind, err := aheadBehind(item.Path, "HEAD", mainBranch); err == nil {
+			st.Main = mainBranch
+			st.MainAhead, st.MainBehind = ahead, behind
+		}
+	}
+	return st
+}
+
+// parseStatusV2 fills counts and upstream info from `git status --porcelain=v2 --branch`
+func parseStatusV2(out []byte, st *Status) {
+	sc := bufio.NewScanner(bytes.NewReader(out))
+	for sc.Scan() {
+		line := sc.Text()
+		switch {
+		case strings.HasPrefix(line, "# branch.upstream "):
+			st.Upstream = strings.TrimPrefix(line, "# branch.upstream ")
+		case strings.HasPrefix(line, "# branch.ab "):
+			var ahead, behind int
+			if _, err := fmt.Sscanf(strings.TrimPrefix(line, "# branch.ab "), "+%d -%d", &ahead, &behind); err == nil {
+				st.Ahead, st.Behind = ahead, behind
+			}
+		case strings.HasPrefix(line, "1 "), strings.HasPrefix(line, "2 "):
+			if len(line) < 4 {
+				continue
+			}
+			if line[2] != '.' {
+				st.Staged++
+			}
+			if line[3] != '.' {
+				st.Unstaged++
+			}
+		case strings.HasPrefix(line, "u "):
+			st.Conflicted++
+		case strings.HasPrefix(line, "? "):
+			st.Untracked++
+		}
+	}
+}
+
+// operationInProgress detects rebase, merge, cherry-pick, revert and bisect
+// state from the worktree's own git dir
+func operationInProgress(path string) string {
+	out, err := gitOut("-C", path, "rev-parse", "--git-dir")
+	if err != nil {
+		return ""
+	}
+	gitDir := string(bytesTrimNL(out))
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(path, gitDir)
+	}
+
+	markers := []struct {
+		file string
+		op   string
+	}{
+		{"rebase-merge", "rebase"},
+		{"rebase-apply", "rebase"},
+		{"MERGE_HEAD", "merge"},
+		{"CHERRY_PICK_HEAD", "cherry-pick"},
+		{"REVERT_HEAD", "revert"},
+		{"BISECT_LOG", "bisect"},
+	}
+	for _, m := range markers {
+		if _, err := os.Stat(filepath.Join(gitDir, m.file)); err == nil {
+			return m.op
+		}
+	}
+	return ""
+}
+
+// aheadBehind counts commits in left that are not in right and vice versa
+func aheadBehind(dir, left, right string) (int, int, error) {
+	out, err := gitOut("-C", dir, "rev-list", "--left-right", "--count", left+"..."+right)
+	if err != nil {
+		return 0, 0, err
+	}
+	fields := strings.Fields(string(out))
+	if len(fields) != 2 {
+		return 0, 0, fmt.Errorf("unexpected rev-list output: %q", out)
+	}
+	ahead, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return 0, 0, err
+	}
+	behind, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return 0, 0, err
+	}
+	return ahead, behind, nil
+}
+
+// CollectStatus inspects all items concurrently with at most workers git
+// processes in flight. Results are returned in the order of items.
+func CollectStatus(items []WorktreeItem, mainBranch string, workers int) []Status {
+	results := make([]Status, len(items))
+	ForEach(len(items), workers, func(i int) {
+		results[i] = GetStatus(items[i], mainBranch)
+	})
+	return results
+}
+
+// ForEach calls fn for 0..n-1 using a bounded pool of workers, so that at
+// most workers calls run at once
+func ForEach(n, workers int, fn func(i int)) {
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > n {
+		workers = n
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				fn(i)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+}
@@ -0,0 +1,188 @@
+package wt
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestRepo creates a repository on branch main with one commit and makes
+// it the current directory
+func newTestRepo(t *testing.T) string {
+	t.Helper()
+	t.Setenv("GIT_AUTHOR_NAME", "Test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "Test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+
+	dir, err := filepath.EvalSymlinks(t.TempDir())
+	require.NoError(t, err)
+	repo := filepath.Join(dir, "repo")
+	require.NoError(t, os.MkdirAll(repo, 0o755))
+
+	runGit(t, repo, "init", "-q", "-b", "main")
+	writeFile(t, repo, "README.md", "# repo\n")
+	runGit(t, repo, "add", "README.md")
+	runGit(t, repo, "commit", "-q", "-m", "Initial commit")
+
+	t.Chdir(repo)
+	return repo
+}
+
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, "git %v: %s", args, out)
+	return string(out)
+}
+
+func writeFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+}
+
+func commitFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	writeFile(t, dir, name, content)
+	runGit(t, dir, "add", name)
+	runGit(t, dir, "commit", "-q", "-m", "update "+name)
+}
+
+func TestGetStatus(t *testing.T) {
+	repo := newTestRepo(t)
+	wtPath := filepath.Join(filepath.Dir(repo), "feature")
+	runGit(t, repo, "worktree", "add", "-q", "-b", "feature/x", wtPath, "main")
+
+	t.Run("clean worktree", func(t *testing.T) {
+		st := GetStatus(WorktreeItem{Path: wtPath, Branch: "feature/x"}, "main")
+		require.NoError(t, st.Err)
+		assert.False(t, st.Dirty())
+		assert.Equal(t, "CLEAN", st.String())
+		assert.Equal(t, "main", st.Main)
+	})
+
+	t.Run("counts staged, unstaged and untracked changes", func(t *testing.T) {
+		writeFile(t, wtPath, "staged.txt", "staged")
+		runGit(t, wtPath, "add", "staged.txt")
+		writeFile(t, wtPath, "README.md", "changed")
+		writeFile(t, wtPath, "new.txt", "untracked")
+
+		st := GetStatus(WorktreeItem{Path: wtPath, Branch: "feature/x"}, "main")
+		assert.Equal(t, 1, st.Staged)
+		assert.Equal(t, 1, st.Unstaged)
+		assert.Equal(t, 1, st.Untracked)
+		assert.True(t, st.Dirty())
+		assert.Equal(t, "1 staged, 1 modified, 1 untracked", st.String())
+
+		runGit(t, wtPath, "reset", "-q", "--hard")
+		runGit(t, wtPath, "clean", "-qfd")
+	})
+
+	t.Run("ahead/behind main", func(t *testing.T) {
+		commitFile(t, wtPath, "a.txt", "a")
+		commitFile(t, wtPath, "b.txt", "b")
+		commitFile(t, repo, "c.txt", "c")
+
+		st := GetStatus(WorktreeItem{Path: wtPath, Branch: "feature/x"}, "main")
+		assert.Equal(t, 2, st.MainAhead)
+		assert.Equal(t, 1, st.MainBehind)
+		assert.Equal(t, "+2 -1", st.MainSummary())
+		assert.Equal(t, "-", st.UpstreamSummary())
+	})
+
+	t.Run("ahead/behind upstream", func(t *testing.T) {
+		runGit(t, wtPath, "branch", "-q", "--set-upstream-to", "main")
+		st := GetStatus(WorktreeItem{Path: wtPath, Branch: "feature/x"}, "")
+		assert.Equal(t, "main", st.Upstream)
+		assert.Equal(t, 2, st.Ahead)
+		assert.Equal(t, 1, st.Behind)
+		assert.Empty(t, st.Main)
+	})
+
+	t.Run("detects merge conflicts", func(t *testing.T) {
+		commitFile(t, repo, "conflict.txt", "main side")
+		commitFile(t, wtPath, "conflict.txt", "feature side")
+		cmd := exec.Command("git", "merge", "main")
+		cmd.Dir = wtPath
+		assert.Error(t, cmd.Run())
+
+		st := GetStatus(WorktreeItem{Path: wtPath, Branch: "feature/x"}, "main")
+		assert.Equal(t, "merge", st.Operation)
+		assert.Equal(t, 1, st.Conflicted)
+		assert.Contains(t, st.String(), "MERGING")
+
+		runGit(t, wtPath, "merge", "--abort")
+	})
+
+	t.Run("missing directory", func(t *testing.T) {
+		st := GetStatus(WorktreeItem{Path: filepath.Join(repo, "gone"), Prunable: true}, "main")
+		assert.Error(t, st.Err)
+		assert.Equal(t, "MISSING (prunable)", st.String())
+	})
+}
+
+func TestCollectStatus(t *testing.T) {
+	repo := newTestRepo(t)
+	var items []WorktreeItem
+	for _, name := range []string{"a", "b", "c"} {
+		path := filepath.Join(filepath.Dir(repo), name)
+		runGit(t, repo, "worktree", "add", "-q", "-b", name, path)
+		items = append(items, WorktreeItem{Path: path, Branch: name})
+	}
+	writeFile(t, items[1].Path, "dirty.txt", "x")
+
+	statuses := CollectStatus(items, "main", 2)
+	require.Len(t, statuses, 3)
+	assert.False(t, statuses[0].Dirty())
+	assert.True(t, statuses[1].Dirty())
+	assert.False(t, statuses[2].Dirty())
+}
+
+func TestList_LockedAndPrunable(t *testing.T) {
+	repo := newTestRepo(t)
+	locked := filepath.Join(filepath.Dir(repo), "locked")
+	gone := filepath.Join(filepath.Dir(repo), "gone")
+	runGit(t, repo, "worktree", "add", "-q", "-b", "locked", locked)
+	runGit(t, repo, "worktree", "add", "-q", "-b", "gone", gone)
+	runGit(t, repo, "worktree", "lock", "--reason", "on usb disk", locked)
+	require.NoError(t, os.RemoveAll(gone))
+
+	items, err := List()
+	require.NoError(t, err)
+	require.Len(t, items, 3)
+
+	byBranch := map[string]WorktreeItem{}
+	for _, it := range items {
+		byBranch[it.Branch] = it
+	}
+	assert.True(t, byBranch["locked"].Locked)
+	assert.Equal(t, "on usb disk", byBranch["locked"].LockReason)
+	assert.False(t, byBranch["locked"].Prunable)
+	assert.True(t, byBranch["gone"].Prunable)
+	assert.NotEmpty(t, byBranch["gone"].PrunableReason)
+	assert.False(t, byBranch["main"].Locked)
+}
+
+func TestForEach(t *testing.T) {
+	var mu sync.Mutex
+	running, peak := 0, 0
+	ForEach(10, 3, func(int) {
+		mu.Lock()
+		running++
+		peak = max(peak, running)
+		mu.Unlock()
+		time.Sleep(5 * time.Millisecond)
+		mu.Lock()
+		running--
+		mu.Unlock()
+	})
+	assert.LessOrEqual(t, peak, 3)
+}
@@ -0,0 +1,143 @@
+package wt
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Submodule is a submodule declared in .gitmodules
+type Submodule struct {
+	Name string
+	Path string
+}
+
+// SubmoduleUpdate reports how a submodule was set up in a new worktree
+type SubmoduleUpdate struct {
+	Submodule
+	Reference string // object store borrowed through --reference, or ""
+}
+
+// HasSubmodules reports whether the worktree at path declares submodules
+func HasSubmodules(path string) bool {
+	_, err := os.Stat(filepath.Join(path, ".gitmodules"))
+	return err == nil
+}
+
+// Submodules lists the submodules declared in the worktree's .gitmodules
+func Submodules(path string) ([]Submodule, error) {
+	if !HasSubmodules(path) {
+		return nil, nil
+	}
+	out, err := gitOut("-C", path, "config", "--file", ".gitmodules", "--get-regexp", `^submodule\..*\.path$`)
+	if err != nil {
+		// git exits with 1 when nothing matches
+		if ee, ok := err.(*exec.ExitError); ok && ee.ExitCode() == 1 {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read .gitmodules in %s: %w", path, err)
+	}
+	var subs []Submodule
+	sc := bufio.NewScanner(bytes.NewReader(out))
+	for sc.Scan() {
+		key, subPath, ok := strings.Cut(sc.Text(), " ")
+		if !ok {
+			continue
+		}
+		name := strings.TrimSuffix(strings.TrimPrefix(key, "submodule."), ".path")
+		subs = append(subs, Submodule{Name: name, Path: subPath})
+	}
+	return subs, nil
+}
+
+// UpdateSubmodules initializes and checks out the submodules of a freshly
+// created worktree. When the main worktree already has a submodule's
+// repository (in .git/modules/<name>), its objects are borrowed with
+// --reference instead of being cloned again. Nested submodules are updated
+// afterwards. git's output goes to progress.
+func UpdateSubmodules(path string, progress io.Writer) ([]SubmoduleUpdate, error) {
+	subs, err := Submodules(path)
+	if err != nil || len(subs) == 0 {
+		return nil, err
+	}
+	common := ""
+	if out, err := gitOut("-C", path, "rev-parse", "--path-format=absolute", "--git-common-dir"); err == nil {
+		common = string(bytesTrimNL(out))
+	}
+
+	gitTo := func(args ...string) error {
+		cmd := exec.Command("git", append([]string{"-C", path}, args...)...)
+		cmd.Stdout = progress
+		cmd.Stderr = progress
+		return cmd.Run()
+	}
+
+	var updated []SubmoduleUpdate
+	for _, sub := range subs {
+		u := SubmoduleUpdate{Submodule: sub}
+		args := []string{"submodule", "update", "--init"}
+		if store := filepath.Join(common, "modules", sub.Name); common != "" {
+			if _, err := os.Stat(filepath.Join(store, "objects")); err == nil {
+				u.Reference = store
+				args = append(args, "--reference", store)
+			}
+		}
+		if err := gitTo(append(args, "--", sub.Path)...); err != nil {
+			return updated, fmt.Errorf("git submodule update failed for %s: %w", sub.Path, err)
+		}
+		updated = append(updated, u)
+	}
+	if err := gitTo("submodule", "update", "--init", "--recursive"); err != nil {
+		return updated, fmt.Errorf("git submodule update --recursive failed: %w", err)
+	}
+	return updated, nil
+}
+
+// DriftedSubmodules lists the submodules of the worktree at path whose
+// checked-out commit differs from the one recorded in the superproject
+func DriftedSubmodules(path string) []string {
+	if !HasSubmodules(path) {
+		return nil
+	}
+	out, err := gitOut("-C", path, "submodule", "status")
+	if err != nil {
+		return nil
+	}
+	var drifted []string
+	sc := bufio.NewScanner(bytes.NewReader(out))
+	for sc.Scan() {
+		line := sc.Text()
+		// "+<sha> path (describe)" marks a different commit checked out
+		if line == "" || line[0] != '+' {
+			continue
+		}
+		if fields := strings.Fields(line[1:]); len(fields) >= 2 {
+			drifted = append(drifted, fields[1])
+		}
+	}
+	return drifted
+}
+
+// SubmoduleUpdateSummary summarizes what UpdateSubmodules did, e.g.
+// "2 submodules (1 reusing local objects)"
+func SubmoduleUpdateSummary(updated []SubmoduleUpdate) string {
+	referenced := 0
+	for _, u := range updated {
+		if u.Reference != "" {
+			referenced++
+		}
+	}
+	noun := "submodules"
+	if len(updated) == 1 {
+		noun = "submodule"
+	}
+	if referenced == 0 {
+		return fmt.Sprintf("%d %s", len(updated), noun)
+	}
+	return fmt.Sprintf("%d %s (%d reusing local objects)", len(updated), noun, referenced)
+}
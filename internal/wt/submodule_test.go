@@ -0,0 +1,87 @@
+package wt
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newSubmoduleRepo returns a test repo with a submodule at libs/lib,
+// initialized in the main worktree
+func newSubmoduleRepo(t *testing.T) string {
+	t.Helper()
+	repo := newTestRepo(t)
+	// Local clones of file:// submodules are disabled by default
+	t.Setenv("GIT_CONFIG_COUNT", "1")
+	t.Setenv("GIT_CONFIG_KEY_0", "protocol.file.allow")
+	t.Setenv("GIT_CONFIG_VALUE_0", "always")
+
+	lib := filepath.Join(filepath.Dir(repo), "lib")
+	require.NoError(t, os.MkdirAll(lib, 0o755))
+	runGit(t, lib, "init", "-q", "-b", "main")
+	commitFile(t, lib, "lib.txt", "v1\n")
+	commitFile(t, lib, "lib.txt", "v2\n")
+
+	runGit(t, repo, "submodule", "add", "-q", lib, "libs/lib")
+	runGit(t, repo, "commit", "-q", "-m", "add lib")
+	return repo
+}
+
+func TestSubmodules(t *testing.T) {
+	repo := newSubmoduleRepo(t)
+	subs, err := Submodules(repo)
+	require.NoError(t, err)
+	assert.Equal(t, []Submodule{{Name: "libs/lib", Path: "libs/lib"}}, subs)
+
+	plain := newTestRepo(t)
+	subs, err = Submodules(plain)
+	require.NoError(t, err)
+	assert.Empty(t, subs)
+}
+
+func TestUpdateSubmodules_ReusesMainStore(t *testing.T) {
+	repo := newSubmoduleRepo(t)
+	path := filepath.Join(filepath.Dir(repo), "feature")
+	runGit(t, repo, "worktree", "add", "-q", "-b", "feature/x", path)
+	assert.NoFileExists(t, filepath.Join(path, "libs", "lib", "lib.txt"))
+
+	updated, err := UpdateSubmodules(path, io.Discard)
+	require.NoError(t, err)
+	require.Len(t, updated, 1)
+	store := filepath.Join(repo, ".git", "modules", "libs", "lib")
+	assert.Equal(t, store, updated[0].Reference)
+	assert.Equal(t, "1 submodule (1 reusing local objects)", SubmoduleUpdateSummary(updated))
+
+	assert.FileExists(t, filepath.Join(path, "libs", "lib", "lib.txt"))
+	gitDir := strings.TrimSpace(runGit(t, filepath.Join(path, "libs", "lib"), "rev-parse", "--absolute-git-dir"))
+	alternates, err := os.ReadFile(filepath.Join(gitDir, "objects", "info", "alternates"))
+	require.NoError(t, err)
+	assert.Contains(t, string(alternates), filepath.Join(store, "objects"))
+	assert.Empty(t, DriftedSubmodules(path))
+}
+
+func TestDriftedSubmodules(t *testing.T) {
+	repo := newSubmoduleRepo(t)
+	assert.Empty(t, DriftedSubmodules(repo))
+
+	runGit(t, filepath.Join(repo, "libs", "lib"), "checkout", "-q", "HEAD~1")
+	assert.Equal(t, []string{"libs/lib"}, DriftedSubmodules(repo))
+
+	st := GetStatus(WorktreeItem{Path: repo, Branch: "main"}, "")
+	require.NoError(t, st.Err)
+	assert.Equal(t, "drifted: libs/lib", st.SubmoduleSummary())
+}
+
+func TestAddWithConfigSilent_InitializesSubmodules(t *testing.T) {
+	repo := newSubmoduleRepo(t)
+	path := filepath.Join(filepath.Dir(repo), "feature")
+
+	_, err := AddWithConfigSilent(path, "feature/x", "HEAD", nil, true)
+	require.NoError(t, err)
+	assert.FileExists(t, filepath.Join(path, "libs", "lib", "lib.txt"))
+}
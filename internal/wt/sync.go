@@ -0,0 +1,150 @@
+package wt
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Sync results
+const (
+	SyncUpdated    = "updated"
+	SyncUpToDate   = "up-to-date"
+	SyncConflicted = "conflicted"
+	SyncSkipped    = "skipped"
+	SyncFailed     = "failed"
+)
+
+// SyncOptions controls how SyncAll updates worktrees
+type SyncOptions struct {
+	Strategy   string // "rebase" (default) or "merge"
+	MainBranch string // used for branches without an upstream
+	Workers    int
+}
+
+// SyncResult is the outcome of updating one worktree
+type SyncResult struct {
+	Item   WorktreeItem
+	Onto   string // upstream or main branch the worktree was updated onto
+	Result string // one of the Sync* constants
+	Detail string
+}
+
+// Fetch fetches all remotes once, pruning deleted branches. Repositories
+// without remotes are left alone.
+func Fetch() error {
+	out, err := gitOut("remote")
+	if err != nil || strings.TrimSpace(string(out)) == "" {
+		return err
+	}
+	if err := git("fetch", "--all", "--prune", "--quiet"); err != nil {
+		return fmt.Errorf("git fetch failed: %w", err)
+	}
+	return nil
+}
+
+// SyncAll rebases or merges every worktree onto its upstream, or onto the main
+// branch when it has none, using at most opts.Workers git processes at once.
+// Results are returned in the order of items.
+func SyncAll(items []WorktreeItem, opts SyncOptions) []SyncResult {
+	results := make([]SyncResult, len(items))
+	ForEach(len(items), opts.Workers, func(i int) {
+		results[i] = SyncWorktree(items[i], opts)
+	})
+	return results
+}
+
+// SyncWorktree updates a single worktree. Dirty trees are autostashed; on
+// conflicts the rebase or merge is aborted so the worktree is left as it was.
+func SyncWorktree(item WorktreeItem, opts SyncOptions) SyncResult {
+	res := SyncResult{Item: item, Result: SyncSkipped}
+
+	switch {
+	case item.Bare:
+		res.Detail = "bare"
+		return res
+	case item.Branch == "":
+		res.Detail = "detached HEAD"
+		return res
+	}
+	if _, err := os.Stat(item.Path); err != nil {
+		res.Detail = "directory missing"
+		return res
+	}
+	if op := operationInProgress(item.Path); op != "" {
+		res.Detail = op + " in progress"
+		return res
+	}
+
+	onto := upstreamRef(item.Path)
+	if onto == "" {
+		onto = opts.MainBranch
+	}
+	if onto == "" || onto == item.Branch || onto == "refs/heads/"+item.Branch {
+		res.Detail = "no upstream"
+		return res
+	}
+	res.Onto = onto
+
+	_, behind, err := aheadBehind(item.Path, "HEAD", onto)
+	if err != nil {
+		res.Result, res.Detail = SyncFailed, fmt.Sprintf("cannot compare with %s", onto)
+		return res
+	}
+	if behind == 0 {
+		res.Result = SyncUpToDate
+		return res
+	}
+
+	strategy := opts.Strategy
+	if strategy == "" {
+		strategy = "rebase"
+	}
+	var args, abort []string
+	switch strategy {
+	case "rebase":
+		args = []string{"rebase", "--autostash", onto}
+		abort = []string{"rebase", "--abort"}
+	case "merge":
+		args = []string{"merge", "--autostash", "--no-edit", onto}
+		abort = []string{"merge", "--abort"}
+	default:
+		res.Result, res.Detail = SyncFailed, fmt.Sprintf("unknown sync strategy %q (use rebase or merge)", strategy)
+		return res
+	}
+
+	out, err := gitIn(item.Path, args...)
+	if err == nil {
+		res.Result, res.Detail = SyncUpdated, fmt.Sprintf("%d new commit(s)", behind)
+		return res
+	}
+	if operationInProgress(item.Path) != "" {
+		_, _ = gitIn(item.Path, abort...)
+		res.Result, res.Detail = SyncConflicted, fmt.Sprintf("%s aborted, run 'git %s %s' to resolve", strategy, strategy, onto)
+		return res
+	}
+	res.Result, res.Detail = SyncFailed, lastLine(out)
+	return res
+}
+
+// upstreamRef returns the short name of the upstream of the worktree's branch
+func upstreamRef(dir string) string {
+	out, err := gitOut("-C", dir, "rev-parse", "--abbrev-ref", "--symbolic-full-name", "@{upstream}")
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// gitIn runs git in dir and returns its combined output
+func gitIn(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	out, err := cmd.CombinedOutput()
+	return string(out), err
+}
+
+func lastLine(s string) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
@@ -0,0 +1,79 @@
+package wt
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyncAll(t *testing.T) {
+	for _, strategy := range []string{"rebase", "merge"} {
+		t.Run(strategy, func(t *testing.T) {
+			repo := newTestRepo(t)
+			parent := filepath.Dir(repo)
+			addWorktree := func(name string) string {
+				path := filepath.Join(parent, name)
+				runGit(t, repo, "worktree", "add", "-q", "-b", "feature/"+name, path, "main")
+				return path
+			}
+
+			behind := addWorktree("behind")
+			commitFile(t, behind, "behind.txt", "own work")
+
+			dirty := addWorktree("dirty")
+			writeFile(t, dirty, "README.md", "# local edit\n")
+
+			conflict := addWorktree("conflict")
+			commitFile(t, conflict, "shared.txt", "ours")
+
+			current := addWorktree("current")
+
+			commitFile(t, repo, "shared.txt", "theirs")
+			runGit(t, current, "merge", "-q", "--ff-only", "main")
+
+			items, err := List()
+			require.NoError(t, err)
+			results := SyncAll(items, SyncOptions{Strategy: strategy, MainBranch: "main", Workers: 4})
+
+			byBranch := map[string]SyncResult{}
+			for _, r := range results {
+				byBranch[r.Item.Branch] = r
+			}
+
+			assert.Equal(t, SyncSkipped, byBranch["main"].Result, "main has no upstream")
+			assert.Equal(t, SyncUpdated, byBranch["feature/behind"].Result, byBranch["feature/behind"].Detail)
+			assert.FileExists(t, filepath.Join(behind, "shared.txt"))
+
+			assert.Equal(t, SyncUpdated, byBranch["feature/dirty"].Result, byBranch["feature/dirty"].Detail)
+			data, err := os.ReadFile(filepath.Join(dirty, "README.md"))
+			require.NoError(t, err)
+			assert.Equal(t, "# local edit\n", string(data), "autostashed changes are restored")
+
+			assert.Equal(t, SyncConflicted, byBranch["feature/conflict"].Result)
+			assert.Empty(t, operationInProgress(conflict), "conflicting update is aborted")
+
+			assert.Equal(t, SyncUpToDate, byBranch["feature/current"].Result)
+		})
+	}
+}
+
+func TestSyncWorktree_OperationInProgress(t *testing.T) {
+	repo := newTestRepo(t)
+	path := filepath.Join(filepath.Dir(repo), "wip")
+	runGit(t, repo, "worktree", "add", "-q", "-b", "feature/wip", path, "main")
+	commitFile(t, path, "shared.txt", "ours")
+	commitFile(t, repo, "shared.txt", "theirs")
+
+	// Leave a conflicted merge behind
+	merge := exec.Command("git", "merge", "main")
+	merge.Dir = path
+	require.Error(t, merge.Run())
+
+	res := SyncWorktree(WorktreeItem{Path: path, Branch: "feature/wip"}, SyncOptions{MainBranch: "main"})
+	assert.Equal(t, SyncSkipped, res.Result)
+	assert.Equal(t, "merge in progress", res.Detail)
+}
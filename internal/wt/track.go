@@ -0,0 +1,77 @@
+package wt
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RemoteBranch is a branch on a remote, known through its remote-tracking ref
+type RemoteBranch struct {
+	Remote string // e.g. origin
+	Branch string // branch name on the remote, e.g. feature/login
+}
+
+// Ref is the short remote-tracking ref, e.g. origin/feature/login
+func (rb RemoteBranch) Ref() string {
+	return rb.Remote + "/" + rb.Branch
+}
+
+// Remotes lists the configured remotes
+func Remotes() ([]string, error) {
+	out, err := gitOut("remote")
+	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(string(out)), nil
+}
+
+// LocalBranchExists reports whether refs/heads/<name> exists
+func LocalBranchExists(name string) bool {
+	return name != "" && gitSilent("show-ref", "--verify", "--quiet", "refs/heads/"+name) == nil
+}
+
+// FindRemoteBranches returns the remote branches name can refer to: either
+// "<remote>/<branch>" naming one remote-tracking branch, or a plain branch
+// name present on one or more remotes.
+func FindRemoteBranches(name string) ([]RemoteBranch, error) {
+	remotes, err := Remotes()
+	if err != nil {
+		return nil, err
+	}
+	exists := func(rb RemoteBranch) bool {
+		return gitSilent("show-ref", "--verify", "--quiet", "refs/remotes/"+rb.Ref()) == nil
+	}
+
+	for _, r := range remotes {
+		if rest, ok := strings.CutPrefix(name, r+"/"); ok && rest != "" {
+			if rb := (RemoteBranch{Remote: r, Branch: rest}); exists(rb) {
+				return []RemoteBranch{rb}, nil
+			}
+		}
+	}
+	var found []RemoteBranch
+	for _, r := range remotes {
+		if rb := (RemoteBranch{Remote: r, Branch: name}); exists(rb) {
+			found = append(found, rb)
+		}
+	}
+	return found, nil
+}
+
+// BranchUpstream returns the short upstream of a local branch, e.g.
+// origin/feature/login, or "" when it has none
+func BranchUpstream(branch string) string {
+	out, err := gitOut("for-each-ref", "--format=%(upstream:short)", "refs/heads/"+branch)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// CreateTrackingBranch creates local branch name at rb and sets rb as its upstream
+func CreateTrackingBranch(name string, rb RemoteBranch) error {
+	if out, err := gitIn(".", "branch", "--track", name, rb.Ref()); err != nil {
+		return fmt.Errorf("failed to create branch %s tracking %s: %s", name, rb.Ref(), lastLine(out))
+	}
+	return nil
+}
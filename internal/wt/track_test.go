@@ -0,0 +1,39 @@
+package wt
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindRemoteBranches(t *testing.T) {
+	repo := newTestRepo(t)
+	parent := filepath.Dir(repo)
+	for _, name := range []string{"origin", "upstream"} {
+		remote := filepath.Join(parent, name+".git")
+		runGit(t, parent, "init", "-q", "--bare", remote)
+		runGit(t, repo, "remote", "add", name, remote)
+		runGit(t, repo, "push", "-q", name, "main:their-branch")
+	}
+	runGit(t, repo, "push", "-q", "origin", "main:only-origin")
+	runGit(t, repo, "fetch", "-q", "--all")
+
+	found, err := FindRemoteBranches("origin/their-branch")
+	require.NoError(t, err)
+	assert.Equal(t, []RemoteBranch{{Remote: "origin", Branch: "their-branch"}}, found)
+
+	found, err = FindRemoteBranches("their-branch")
+	require.NoError(t, err)
+	assert.Equal(t, []RemoteBranch{{"origin", "their-branch"}, {"upstream", "their-branch"}}, found)
+
+	found, err = FindRemoteBranches("nowhere")
+	require.NoError(t, err)
+	assert.Empty(t, found)
+
+	require.NoError(t, CreateTrackingBranch("only-origin", RemoteBranch{Remote: "origin", Branch: "only-origin"}))
+	assert.True(t, LocalBranchExists("only-origin"))
+	assert.Equal(t, "origin/only-origin", BranchUpstream("only-origin"))
+	assert.Empty(t, BranchUpstream("main"))
+}
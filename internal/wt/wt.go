@@ -35,14 +35,79 @@ func gitOut(args ...string) ([]byte, error) {
 	return runOut("git", args...)
 }
 
+// repoRoot returns the top of the current worktree. Outside any worktree of
+// a bare layout (see Clone) it returns the layout's root directory.
 func repoRoot() (string, error) {
 	out, err := gitOut("rev-parse", "--show-toplevel")
 	if err != nil {
+		if common, cerr := commonDir(); cerr == nil {
+			if root, ok := bareLayoutRoot(common); ok {
+				return root, nil
+			}
+		}
 		return "", fmt.Errorf("not a git repository (run inside a repo)")
 	}
 	return filepath.Clean(string(bytesTrimNL(out))), nil
 }
 
+// MainRoot returns the root of the main worktree, i.e. the directory holding
+// .git and .gitwo/, even when called from inside a linked worktree. In a bare
+// layout it is the directory holding .bare, .git and the worktrees.
+func MainRoot() (string, error) {
+	top, err := repoRoot()
+	if err != nil {
+		return "", err
+	}
+	common, err := commonDir()
+	if err != nil {
+		return top, nil
+	}
+	if filepath.Base(common) == ".git" {
+		return filepath.Dir(common), nil
+	}
+	if root, ok := bareLayoutRoot(common); ok {
+		return root, nil
+	}
+	return top, nil
+}
+
+// bareLayoutRoot recognizes the bare layout: a bare repository whose parent
+// directory has a .git file pointing at it. It returns that parent.
+func bareLayoutRoot(common string) (string, bool) {
+	root := filepath.Dir(common)
+	data, err := os.ReadFile(filepath.Join(root, ".git"))
+	if err != nil {
+		return "", false
+	}
+	gitdir, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir:")
+	if !ok {
+		return "", false
+	}
+	gitdir = strings.TrimSpace(gitdir)
+	if !filepath.IsAbs(gitdir) {
+		gitdir = filepath.Join(root, gitdir)
+	}
+	if canonicalPath(gitdir) != canonicalPath(common) {
+		return "", false
+	}
+	return root, true
+}
+
+// commonDir returns the absolute path of the repository's common git dir
+func commonDir() (string, error) {
+	out, err := gitOut("rev-parse", "--git-common-dir")
+	if err != nil {
+		return "", err
+	}
+	dir := string(bytesTrimNL(out))
+	if !filepath.IsAbs(dir) {
+		if dir, err = filepath.Abs(dir); err != nil {
+			return "", err
+		}
+	}
+	return filepath.Clean(dir), nil
+}
+
 func bytesTrimNL(b []byte) []byte {
 	return bytes.TrimRight(b, "\r\n")
 }